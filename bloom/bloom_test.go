@@ -0,0 +1,137 @@
+package bloom
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCountingBloomFilter(t *testing.T) {
+	filter, err := NewCounting(BloomFilterConfig{Bits: 1 << 12})
+	if err != nil {
+		t.Fatalf("new counting filter failed: %v", err)
+	}
+
+	filter.Add([]byte("a"))
+	filter.Add([]byte("b"))
+	filter.Add([]byte("b"))
+
+	filter.Remove([]byte("a"))
+	if ok, _ := filter.Exists([]byte("a")); ok {
+		t.Errorf("expected a is removed")
+	}
+
+	filter.Remove([]byte("b"))
+	if ok, _ := filter.Exists([]byte("b")); !ok {
+		t.Errorf("expected b exists after removing once of twice")
+	}
+
+	filter.Remove([]byte("b"))
+	if ok, _ := filter.Exists([]byte("b")); ok {
+		t.Errorf("expected b is removed")
+	}
+
+	// removing an absent item does not change the counters
+	filter.Add([]byte("c"))
+	filter.Remove([]byte("absent"))
+	if ok, _ := filter.Exists([]byte("c")); !ok {
+		t.Errorf("expected c exists")
+	}
+
+	if _, err := NewCounting(BloomFilterConfig{Bits: 8, BitSet: newBitSet(8)}); err == nil {
+		t.Errorf("expected error for a bitset without counters")
+	}
+}
+
+func TestScalableBloomFilter(t *testing.T) {
+	const (
+		n         = 10000
+		errorRate = 0.01
+	)
+
+	filter, err := NewScalable(ScalableBloomFilterConfig{
+		InitialCapacity: 100,
+		ErrorRate:       errorRate,
+	})
+	if err != nil {
+		t.Fatalf("new scalable filter failed: %v", err)
+	}
+
+	for i := 0; i < n; i++ {
+		filter.Add([]byte(fmt.Sprintf("item-%d", i)))
+	}
+
+	if filter.Len() < 2 {
+		t.Errorf("expected filter grows, but got %d filters", filter.Len())
+	}
+
+	for i := 0; i < n; i++ {
+		if ok, _ := filter.Exists([]byte(fmt.Sprintf("item-%d", i))); !ok {
+			t.Fatalf("expected item-%d exists", i)
+		}
+	}
+
+	positives := 0
+	for i := 0; i < n; i++ {
+		if ok, _ := filter.Exists([]byte(fmt.Sprintf("absent-%d", i))); ok {
+			positives++
+		}
+	}
+	if rate := float64(positives) / n; rate > 2*errorRate {
+		t.Errorf("expected false positive rate under %v, but got %v", errorRate, rate)
+	}
+
+	filter.Reset()
+	if filter.Len() != 1 {
+		t.Errorf("expected 1 filter after reset, but got %d", filter.Len())
+	}
+	if ok, _ := filter.Exists([]byte("item-0")); ok {
+		t.Errorf("expected item-0 is reset")
+	}
+}
+
+func TestScalableBloomFilterSharedState(t *testing.T) {
+	// bit sets and state shared by replicas, like the redis ones
+	bitsets := map[int]BitSet{}
+	state := &memoryState{}
+	config := ScalableBloomFilterConfig{
+		NewBitSet: func(i, bits int) (BitSet, error) {
+			if _, ok := bitsets[i]; !ok {
+				bitsets[i] = newBitSet(bits)
+			}
+			return bitsets[i], nil
+		},
+		State:           state,
+		InitialCapacity: 10,
+		ErrorRate:       0.01,
+	}
+
+	if _, err := NewScalable(ScalableBloomFilterConfig{NewBitSet: config.NewBitSet, InitialCapacity: 10, ErrorRate: 0.01}); err == nil {
+		t.Errorf("expected error without state")
+	}
+
+	first, _ := NewScalable(config)
+	for i := 0; i < 100; i++ {
+		first.Add([]byte(fmt.Sprintf("item-%d", i)))
+	}
+
+	// a restarted replica finds the items in all filters
+	second, _ := NewScalable(config)
+	for i := 0; i < 100; i++ {
+		if ok, _ := second.Exists([]byte(fmt.Sprintf("item-%d", i))); !ok {
+			t.Fatalf("expected item-%d exists", i)
+		}
+	}
+	if second.Len() != first.Len() {
+		t.Errorf("expected %d filters, but got %d", first.Len(), second.Len())
+	}
+
+	// and keeps filling the last filter instead of the first one
+	second.Add([]byte("more"))
+	counts, _ := state.Counts()
+	if counts[0] != config.InitialCapacity {
+		t.Errorf("expected first filter keeps %d items, but got %d", config.InitialCapacity, counts[0])
+	}
+	if ok, _ := first.Exists([]byte("more")); !ok {
+		t.Errorf("expected item added by another replica exists")
+	}
+}
@@ -0,0 +1,104 @@
+package bloom
+
+import (
+	"fmt"
+	"math"
+)
+
+// CountingBitSet is a BitSet of counters, so items can be removed.
+type CountingBitSet interface {
+	BitSet
+	// Remove decrements the counters of the items if none of them is zero.
+	Remove(items []int, opts ...Option) error
+}
+
+func newCountingBitSet(size int) CountingBitSet {
+	counters := make(counterSet, size)
+	return &counters
+}
+
+// counterSet keeps a counter per bit, a counter sticks at the max value
+// once it overflows, as its real count is unknown.
+type counterSet []uint8
+
+func (c *counterSet) Add(items []int, opts ...Option) error {
+	for _, item := range items {
+		if (*c)[item] < math.MaxUint8 {
+			(*c)[item]++
+		}
+	}
+	return nil
+}
+
+func (c *counterSet) Exists(items []int, opts ...Option) (bool, error) {
+	for _, item := range items {
+		if (*c)[item] == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (c *counterSet) Remove(items []int, opts ...Option) error {
+	if exists, _ := c.Exists(items); !exists {
+		return nil
+	}
+
+	for _, item := range items {
+		if count := (*c)[item]; count > 0 && count < math.MaxUint8 {
+			(*c)[item]--
+		}
+	}
+	return nil
+}
+
+func (c *counterSet) Reset(opts ...Option) error {
+	for i := range *c {
+		(*c)[i] = 0
+	}
+	return nil
+}
+
+// CountingBloomFilter is a BloomFilter which supports Remove, its BitSet
+// must be a CountingBitSet, counters in memory by default.
+type CountingBloomFilter struct {
+	*BloomFilter
+	counters CountingBitSet
+}
+
+func NewCounting(config BloomFilterConfig) (*CountingBloomFilter, error) {
+	if config.Bits <= 0 {
+		return nil, fmt.Errorf("bits must great than zero")
+	}
+
+	if config.BitSet == nil {
+		config.BitSet = newCountingBitSet(config.Bits)
+	}
+
+	counters, ok := config.BitSet.(CountingBitSet)
+	if !ok {
+		return nil, fmt.Errorf("bitset must be a CountingBitSet")
+	}
+
+	filter, err := New(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CountingBloomFilter{
+		BloomFilter: filter,
+		counters:    counters,
+	}, nil
+}
+
+// Remove removes the data, it must have been added, otherwise other data
+// may not be found any more.
+func (f *CountingBloomFilter) Remove(data []byte, opts ...Option) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	locations := f.getLocations(data)
+
+	return f.counters.Remove(locations, opts...)
+}
@@ -0,0 +1,106 @@
+package redisbitset
+
+import (
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/qingwave/gocorex/bloom"
+)
+
+// counters are stored as unsigned 8-bit integers by BITFIELD, a counter
+// sticks at 255 once it overflows.
+const (
+	// increase counters lua script
+	incrScript = `
+for _, offset in ipairs(ARGV) do
+	redis.call("bitfield", KEYS[1], "overflow", "sat", "incrby", "u8", "#" .. offset, 1)
+end
+`
+	// check counters lua script
+	countScript = `
+for _, offset in ipairs(ARGV) do
+	if redis.call("bitfield", KEYS[1], "get", "u8", "#" .. offset)[1] == 0 then
+		return false
+	end
+end
+return true
+`
+	// decrease counters lua script, only if none of them is zero
+	decrScript = `
+for _, offset in ipairs(ARGV) do
+	if redis.call("bitfield", KEYS[1], "get", "u8", "#" .. offset)[1] == 0 then
+		return false
+	end
+end
+for _, offset in ipairs(ARGV) do
+	local count = redis.call("bitfield", KEYS[1], "get", "u8", "#" .. offset)[1]
+	if count > 0 and count < 255 then
+		redis.call("bitfield", KEYS[1], "incrby", "u8", "#" .. offset, -1)
+	end
+end
+return true
+`
+)
+
+func NewCounting(client *redis.Client, key string) (bloom.CountingBitSet, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client must not be nil")
+	}
+
+	if key == "" {
+		return nil, fmt.Errorf("key must not be empty")
+	}
+
+	return &RedisCountingBitSet{
+		client: client,
+		key:    key,
+	}, nil
+}
+
+type RedisCountingBitSet struct {
+	client *redis.Client
+	key    string
+}
+
+func (r *RedisCountingBitSet) Reset(opts ...bloom.Option) error {
+	ctx := bloom.NewFilterOptions(opts...).Context
+	return r.client.Del(ctx, r.key).Err()
+}
+
+func (r *RedisCountingBitSet) Add(items []int, opts ...bloom.Option) error {
+	return r.eval(incrScript, items, opts...)
+}
+
+func (r *RedisCountingBitSet) Remove(items []int, opts ...bloom.Option) error {
+	return r.eval(decrScript, items, opts...)
+}
+
+func (r *RedisCountingBitSet) Exists(items []int, opts ...bloom.Option) (bool, error) {
+	ctx := bloom.NewFilterOptions(opts...).Context
+
+	resp, err := r.client.Eval(ctx, countScript, []string{r.key}, getArgs(items)).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, err
+	}
+
+	exists, ok := resp.(int64)
+	if !ok {
+		return false, nil
+	}
+
+	return exists == 1, nil
+}
+
+func (r *RedisCountingBitSet) eval(script string, items []int, opts ...bloom.Option) error {
+	ctx := bloom.NewFilterOptions(opts...).Context
+
+	_, err := r.client.Eval(ctx, script, []string{r.key}, getArgs(items)).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	return nil
+}
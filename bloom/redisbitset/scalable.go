@@ -0,0 +1,69 @@
+package redisbitset
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/qingwave/gocorex/bloom"
+)
+
+// NewScalableState returns the state of a scalable bloom filter, the number
+// of items of every filter is kept in the hash key, field i for the i-th one.
+func NewScalableState(client *redis.Client, key string) (bloom.ScalableState, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client must not be nil")
+	}
+
+	if key == "" {
+		return nil, fmt.Errorf("key must not be empty")
+	}
+
+	return &RedisScalableState{
+		client: client,
+		key:    key,
+	}, nil
+}
+
+type RedisScalableState struct {
+	client *redis.Client
+	key    string
+}
+
+func (r *RedisScalableState) Counts(opts ...bloom.Option) ([]int, error) {
+	ctx := bloom.NewFilterOptions(opts...).Context
+
+	fields, err := r.client.HGetAll(ctx, r.key).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	var counts []int
+	for field, val := range fields {
+		i, err := strconv.Atoi(field)
+		if err != nil || i < 0 {
+			return nil, fmt.Errorf("invaild filter index %q", field)
+		}
+		count, err := strconv.Atoi(val)
+		if err != nil {
+			return nil, fmt.Errorf("invaild count %q of filter %d", val, i)
+		}
+
+		for len(counts) <= i {
+			counts = append(counts, 0)
+		}
+		counts[i] = count
+	}
+
+	return counts, nil
+}
+
+func (r *RedisScalableState) Incr(i int, opts ...bloom.Option) error {
+	ctx := bloom.NewFilterOptions(opts...).Context
+	return r.client.HIncrBy(ctx, r.key, strconv.Itoa(i), 1).Err()
+}
+
+func (r *RedisScalableState) Reset(opts ...bloom.Option) error {
+	ctx := bloom.NewFilterOptions(opts...).Context
+	return r.client.Del(ctx, r.key).Err()
+}
@@ -0,0 +1,251 @@
+package bloom
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+const (
+	defaultGrowthFactor    = 2
+	defaultTighteningRatio = 0.5
+)
+
+// ScalableState keeps the number of items added to every filter of a
+// ScalableBloomFilter, so the filters sharing the bit sets, e.g. replicas
+// or a restarted process, agree on how many filters there are.
+type ScalableState interface {
+	// Counts returns the number of items of every filter.
+	Counts(opts ...Option) ([]int, error)
+	// Incr increases the number of items of the i-th filter by one.
+	Incr(i int, opts ...Option) error
+	Reset(opts ...Option) error
+}
+
+type ScalableBloomFilterConfig struct {
+	// NewBitSet creates the BitSet of the i-th filter with the given bits,
+	// e.g. a redis bitset with the key suffixed by i, in memory if it is nil.
+	NewBitSet func(i, bits int) (BitSet, error)
+	// State must be set along with NewBitSet, e.g. a redis hash next to the
+	// bit sets, it is kept in memory if NewBitSet is nil.
+	State ScalableState
+	// InitialCapacity is the number of items of the first filter.
+	InitialCapacity int
+	// ErrorRate is the max false positive rate of the whole filter.
+	ErrorRate float64
+	// GrowthFactor is the ratio of capacity of a filter to the previous one, 2 by default.
+	GrowthFactor int
+	// TighteningRatio is the ratio of error rate of a filter to the previous one, 0.5 by default.
+	TighteningRatio float64
+}
+
+// ScalableBloomFilter chains bloom filters, a new filter with a larger
+// capacity and a lower error rate is added when the last one is full, so
+// the error rate of the whole filter stays under ErrorRate as items are
+// added. Filters sharing the bit sets and the state must have the same config.
+type ScalableBloomFilter struct {
+	ScalableBloomFilterConfig
+
+	mu      sync.Mutex
+	filters []*scalableLayer
+}
+
+type scalableLayer struct {
+	*BloomFilter
+	capacity int
+	// errorRate is the false positive rate of this filter
+	errorRate float64
+}
+
+func NewScalable(config ScalableBloomFilterConfig) (*ScalableBloomFilter, error) {
+	if config.InitialCapacity <= 0 {
+		return nil, fmt.Errorf("initial capacity must great than zero")
+	}
+
+	if config.ErrorRate <= 0 || config.ErrorRate >= 1 {
+		return nil, fmt.Errorf("error rate must be in (0, 1)")
+	}
+
+	if config.State == nil {
+		if config.NewBitSet != nil {
+			return nil, fmt.Errorf("state must not be nil with NewBitSet")
+		}
+		config.State = &memoryState{}
+	}
+
+	if config.GrowthFactor <= 0 {
+		config.GrowthFactor = defaultGrowthFactor
+	}
+
+	if config.TighteningRatio <= 0 || config.TighteningRatio >= 1 {
+		config.TighteningRatio = defaultTighteningRatio
+	}
+
+	f := &ScalableBloomFilter{ScalableBloomFilterConfig: config}
+	// the error rates sum up to ErrorRate as a geometric series
+	if err := f.grow(config.InitialCapacity, config.ErrorRate*(1-config.TighteningRatio)); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *ScalableBloomFilter) grow(capacity int, errorRate float64) error {
+	// optimal bits and hash functions for the capacity and error rate
+	bits := int(math.Ceil(-float64(capacity) * math.Log(errorRate) / (math.Ln2 * math.Ln2)))
+	maps := int(math.Max(1, math.Round(float64(bits)/float64(capacity)*math.Ln2)))
+
+	config := BloomFilterConfig{Bits: bits, Maps: maps}
+	if f.NewBitSet != nil {
+		bitset, err := f.NewBitSet(len(f.filters), bits)
+		if err != nil {
+			return err
+		}
+		config.BitSet = bitset
+	}
+
+	filter, err := New(config)
+	if err != nil {
+		return err
+	}
+
+	f.filters = append(f.filters, &scalableLayer{
+		BloomFilter: filter,
+		capacity:    capacity,
+		errorRate:   errorRate,
+	})
+	return nil
+}
+
+func (f *ScalableBloomFilter) growNext() error {
+	last := f.filters[len(f.filters)-1]
+	return f.grow(last.capacity*f.GrowthFactor, last.errorRate*f.TighteningRatio)
+}
+
+// sync loads the counts from the state and adds the filters added by others.
+func (f *ScalableBloomFilter) sync(opts ...Option) ([]int, error) {
+	counts, err := f.State.Counts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for len(f.filters) < len(counts) {
+		if err := f.growNext(); err != nil {
+			return nil, err
+		}
+	}
+
+	return counts, nil
+}
+
+func (f *ScalableBloomFilter) Add(data []byte, opts ...Option) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	counts, err := f.sync(opts...)
+	if err != nil {
+		return err
+	}
+
+	// do not count the data added before
+	exists, err := f.exists(data, opts...)
+	if exists || err != nil {
+		return err
+	}
+
+	i := len(f.filters) - 1
+	if i < len(counts) && counts[i] >= f.filters[i].capacity {
+		if err := f.growNext(); err != nil {
+			return err
+		}
+		i++
+	}
+
+	// count first, so others check the filter once the data is in it
+	if err := f.State.Incr(i, opts...); err != nil {
+		return err
+	}
+
+	return f.filters[i].Add(data, opts...)
+}
+
+func (f *ScalableBloomFilter) Exists(data []byte, opts ...Option) (bool, error) {
+	if len(data) == 0 {
+		return false, nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.sync(opts...); err != nil {
+		return false, err
+	}
+
+	return f.exists(data, opts...)
+}
+
+func (f *ScalableBloomFilter) exists(data []byte, opts ...Option) (bool, error) {
+	for _, filter := range f.filters {
+		exists, err := filter.Exists(data, opts...)
+		if exists || err != nil {
+			return exists, err
+		}
+	}
+	return false, nil
+}
+
+// Reset resets the state and all filters, and drops all but the first one.
+func (f *ScalableBloomFilter) Reset(opts ...Option) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.sync(opts...); err != nil {
+		return err
+	}
+
+	if err := f.State.Reset(opts...); err != nil {
+		return err
+	}
+
+	for _, filter := range f.filters {
+		if err := filter.Reset(opts...); err != nil {
+			return err
+		}
+	}
+
+	f.filters = f.filters[:1]
+	return nil
+}
+
+// Len returns the number of filters.
+func (f *ScalableBloomFilter) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.filters)
+}
+
+// memoryState is the ScalableState in memory, guarded by the filter.
+type memoryState struct {
+	counts []int
+}
+
+func (s *memoryState) Counts(opts ...Option) ([]int, error) {
+	return s.counts, nil
+}
+
+func (s *memoryState) Incr(i int, opts ...Option) error {
+	for len(s.counts) <= i {
+		s.counts = append(s.counts, 0)
+	}
+	s.counts[i]++
+	return nil
+}
+
+func (s *memoryState) Reset(opts ...Option) error {
+	s.counts = nil
+	return nil
+}
@@ -0,0 +1,55 @@
+package containerx
+
+import (
+	"cmp"
+	"iter"
+	"math"
+	"slices"
+)
+
+// Number is the constraint of the items which can be summed up.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sum returns the sum of the items, e.g. Sum(ring.All()) over a window of samples.
+func Sum[T Number](seq iter.Seq[T]) T {
+	var sum T
+	for item := range seq {
+		sum += item
+	}
+	return sum
+}
+
+// Mean returns the arithmetic mean of the items, zero if there is none.
+func Mean[T Number](seq iter.Seq[T]) float64 {
+	sum, count := 0.0, 0
+	for item := range seq {
+		sum += float64(item)
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// Percentile returns the p-th percentile of the items by the nearest-rank
+// method, p is in [0, 100], e.g. 99 for the p99 latency. It returns zero
+// if there is no item.
+func Percentile[T cmp.Ordered](seq iter.Seq[T], p float64) T {
+	items := slices.Sorted(seq)
+	if len(items) == 0 {
+		var t T
+		return t
+	}
+
+	p = math.Max(0, math.Min(100, p))
+	rank := int(math.Ceil(p / 100 * float64(len(items))))
+	if rank < 1 {
+		rank = 1
+	}
+	return items[rank-1]
+}
@@ -0,0 +1,168 @@
+package containerx
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"slices"
+	"sync"
+)
+
+var (
+	ErrQueueFull   = errors.New("queue is full")
+	ErrQueueEmpty  = errors.New("queue is empty")
+	ErrQueueClosed = errors.New("queue is closed")
+)
+
+// BlockingQueue is a bounded FIFO queue safe for concurrent use, Push blocks
+// while it is full and Pop blocks while it is empty, so it applies
+// backpressure between producers and consumers.
+//
+// After Close, Push fails with ErrQueueClosed and Pop returns the remaining
+// items, then fails with ErrQueueClosed.
+type BlockingQueue[T any] struct {
+	mu       sync.Mutex
+	items    Deque[T]
+	capacity int
+	closed   bool
+	// changed is closed and replaced when the queue changes, to wake up
+	// the blocked callers
+	changed chan struct{}
+}
+
+func NewBlockingQueue[T any](capacity int) *BlockingQueue[T] {
+	if capacity <= 0 {
+		panic("invaild queue capacity")
+	}
+
+	return &BlockingQueue[T]{
+		capacity: capacity,
+		changed:  make(chan struct{}),
+	}
+}
+
+// Push adds the item, it blocks until there is room or the queue is closed.
+func (q *BlockingQueue[T]) Push(item T) error {
+	return q.PushCtx(context.Background(), item)
+}
+
+// PushCtx is like Push but gives up with the error of ctx when it is done.
+func (q *BlockingQueue[T]) PushCtx(ctx context.Context, item T) error {
+	for {
+		changed, err := q.push(item)
+		if err != ErrQueueFull {
+			return err
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// TryPush adds the item without blocking, it returns ErrQueueFull if there
+// is no room.
+func (q *BlockingQueue[T]) TryPush(item T) error {
+	_, err := q.push(item)
+	return err
+}
+
+// push adds the item if there is room, otherwise it returns the channel to
+// wait on for a change, which is taken with the check under the lock so
+// that no change is missed.
+func (q *BlockingQueue[T]) push(item T) (<-chan struct{}, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil, ErrQueueClosed
+	}
+	if q.items.Len() >= q.capacity {
+		return q.changed, ErrQueueFull
+	}
+
+	q.items.PushBack(item)
+	q.broadcast()
+	return nil, nil
+}
+
+// Pop removes the front item, it blocks until there is one or the queue
+// is closed.
+func (q *BlockingQueue[T]) Pop() (T, error) {
+	return q.PopCtx(context.Background())
+}
+
+// PopCtx is like Pop but gives up with the error of ctx when it is done.
+func (q *BlockingQueue[T]) PopCtx(ctx context.Context) (T, error) {
+	for {
+		item, changed, err := q.pop()
+		if err != ErrQueueEmpty {
+			return item, err
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return item, ctx.Err()
+		}
+	}
+}
+
+// TryPop removes the front item without blocking, it returns ErrQueueEmpty
+// if there is none.
+func (q *BlockingQueue[T]) TryPop() (T, error) {
+	item, _, err := q.pop()
+	return item, err
+}
+
+// pop removes the front item if there is one, otherwise it returns the
+// channel to wait on for a change, see push.
+func (q *BlockingQueue[T]) pop() (T, <-chan struct{}, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	item, ok := q.items.PopFront()
+	if ok {
+		q.broadcast()
+		return item, nil, nil
+	}
+	if q.closed {
+		return item, nil, ErrQueueClosed
+	}
+	return item, q.changed, ErrQueueEmpty
+}
+
+// Close wakes up the blocked callers, it is safe to call it more than once.
+func (q *BlockingQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		q.broadcast()
+	}
+}
+
+func (q *BlockingQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Len()
+}
+
+// All returns an iterator over a snapshot of the items from the front.
+func (q *BlockingQueue[T]) All() iter.Seq[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Values(q.items.Slice())
+}
+
+func (q *BlockingQueue[T]) Cap() int {
+	return q.capacity
+}
+
+func (q *BlockingQueue[T]) broadcast() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
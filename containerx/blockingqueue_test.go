@@ -0,0 +1,116 @@
+package containerx
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBlockingQueue(t *testing.T) {
+	q := NewBlockingQueue[int](2)
+
+	if _, err := q.TryPop(); err != ErrQueueEmpty {
+		t.Errorf("expected %v, but got %v", ErrQueueEmpty, err)
+	}
+	if err := q.TryPush(1); err != nil {
+		t.Errorf("expected push succeed, but got %v", err)
+	}
+	q.Push(2)
+	if err := q.TryPush(3); err != ErrQueueFull {
+		t.Errorf("expected %v, but got %v", ErrQueueFull, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := q.PushCtx(ctx, 3); err != context.DeadlineExceeded {
+		t.Errorf("expected %v, but got %v", context.DeadlineExceeded, err)
+	}
+
+	// a blocked push continues after a pop
+	pushed := make(chan error)
+	go func() {
+		pushed <- q.Push(3)
+	}()
+	if item, err := q.Pop(); err != nil || item != 1 {
+		t.Errorf("expected pop 1, but got %d, %v", item, err)
+	}
+	if err := <-pushed; err != nil {
+		t.Errorf("expected push succeed, but got %v", err)
+	}
+
+	q.Close()
+	q.Close()
+	if err := q.Push(4); err != ErrQueueClosed {
+		t.Errorf("expected %v, but got %v", ErrQueueClosed, err)
+	}
+	for _, expected := range []int{2, 3} {
+		if item, err := q.Pop(); err != nil || item != expected {
+			t.Errorf("expected pop %d after close, but got %d, %v", expected, item, err)
+		}
+	}
+	if _, err := q.Pop(); err != ErrQueueClosed {
+		t.Errorf("expected %v, but got %v", ErrQueueClosed, err)
+	}
+}
+
+func TestBlockingQueueCloseWakesUp(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+
+	popped := make(chan error)
+	go func() {
+		_, err := q.PopCtx(context.Background())
+		popped <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	q.Close()
+
+	select {
+	case err := <-popped:
+		if err != ErrQueueClosed {
+			t.Errorf("expected %v, but got %v", ErrQueueClosed, err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected blocked pop woken up by close")
+	}
+}
+
+func TestBlockingQueueConcurrently(t *testing.T) {
+	q := NewBlockingQueue[int](4)
+
+	var (
+		mu  sync.Mutex
+		sum int
+		wg  sync.WaitGroup
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				item, err := q.Pop()
+				if err != nil {
+					return
+				}
+				mu.Lock()
+				sum += item
+				mu.Unlock()
+			}
+		}()
+	}
+
+	parallel(func(worker int) {
+		for i := 1; i <= perWork; i++ {
+			if err := q.Push(i); err != nil {
+				t.Errorf("expected push succeed, but got %v", err)
+			}
+		}
+	})
+	q.Close()
+	wg.Wait()
+
+	if expected := workers * perWork * (perWork + 1) / 2; sum != expected {
+		t.Errorf("expected sum %d, but got %d", expected, sum)
+	}
+}
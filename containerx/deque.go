@@ -0,0 +1,156 @@
+package containerx
+
+import "iter"
+
+const minDequeCap = 8
+
+// Deque is a double-ended queue backed by a ring buffer, which grows when
+// it is full and shrinks when it is a quarter full. The zero value is an
+// empty deque ready to use.
+type Deque[T any] struct {
+	buf   []T
+	head  int
+	count int
+}
+
+func NewDeque[T any](items ...T) *Deque[T] {
+	d := &Deque[T]{}
+	for _, item := range items {
+		d.PushBack(item)
+	}
+	return d
+}
+
+func (d *Deque[T]) Len() int {
+	return d.count
+}
+
+func (d *Deque[T]) Empty() bool {
+	return d.count == 0
+}
+
+func (d *Deque[T]) PushBack(item T) {
+	d.grow()
+	d.buf[d.index(d.count)] = item
+	d.count++
+}
+
+func (d *Deque[T]) PushFront(item T) {
+	d.grow()
+	d.head = d.index(len(d.buf) - 1)
+	d.buf[d.head] = item
+	d.count++
+}
+
+func (d *Deque[T]) PopFront() (T, bool) {
+	var t T
+	if d.count == 0 {
+		return t, false
+	}
+
+	item := d.buf[d.head]
+	d.buf[d.head] = t
+	d.head = d.index(1)
+	d.count--
+	d.shrink()
+	return item, true
+}
+
+func (d *Deque[T]) PopBack() (T, bool) {
+	var t T
+	if d.count == 0 {
+		return t, false
+	}
+
+	back := d.index(d.count - 1)
+	item := d.buf[back]
+	d.buf[back] = t
+	d.count--
+	d.shrink()
+	return item, true
+}
+
+func (d *Deque[T]) Front() (T, bool) {
+	if d.count == 0 {
+		var t T
+		return t, false
+	}
+	return d.buf[d.head], true
+}
+
+func (d *Deque[T]) Back() (T, bool) {
+	if d.count == 0 {
+		var t T
+		return t, false
+	}
+	return d.buf[d.index(d.count-1)], true
+}
+
+// At returns the i-th item from the front, it panics if i is out of range.
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.count {
+		panic("deque index out of range")
+	}
+	return d.buf[d.index(i)]
+}
+
+// All returns an iterator over the items from the front, the deque must
+// not be modified during the iteration.
+func (d *Deque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < d.count; i++ {
+			if !yield(d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Slice returns the items from the front to the back.
+func (d *Deque[T]) Slice() []T {
+	items := make([]T, d.count)
+	for i := range items {
+		items[i] = d.buf[d.index(i)]
+	}
+	return items
+}
+
+// Clear removes all items and releases the buffer.
+func (d *Deque[T]) Clear() {
+	d.buf, d.head, d.count = nil, 0, 0
+}
+
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) % len(d.buf)
+}
+
+func (d *Deque[T]) grow() {
+	if d.count < len(d.buf) {
+		return
+	}
+
+	size := len(d.buf) * 2
+	if size == 0 {
+		size = minDequeCap
+	}
+	d.resize(size)
+}
+
+func (d *Deque[T]) shrink() {
+	if len(d.buf) > minDequeCap && d.count <= len(d.buf)/4 {
+		d.resize(len(d.buf) / 2)
+	}
+}
+
+func (d *Deque[T]) resize(size int) {
+	buf := make([]T, size)
+	if d.count > 0 {
+		if end := d.head + d.count; end <= len(d.buf) {
+			copy(buf, d.buf[d.head:end])
+		} else {
+			n := copy(buf, d.buf[d.head:])
+			copy(buf[n:], d.buf[:end-len(d.buf)])
+		}
+	}
+	d.buf, d.head = buf, 0
+}
@@ -0,0 +1,80 @@
+package containerx
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDeque(t *testing.T) {
+	var d Deque[int]
+	if _, ok := d.Front(); ok {
+		t.Errorf("expected no front of empty deque")
+	}
+
+	// compare with a slice model
+	var model []int
+	for i := 0; i < 10000; i++ {
+		switch rand.Intn(4) {
+		case 0:
+			d.PushBack(i)
+			model = append(model, i)
+		case 1:
+			d.PushFront(i)
+			model = append([]int{i}, model...)
+		case 2:
+			item, ok := d.PopFront()
+			if ok != (len(model) > 0) || ok && item != model[0] {
+				t.Fatalf("pop front: expected %v, but got %d, %t", model, item, ok)
+			}
+			if ok {
+				model = model[1:]
+			}
+		case 3:
+			item, ok := d.PopBack()
+			if ok != (len(model) > 0) || ok && item != model[len(model)-1] {
+				t.Fatalf("pop back: expected %v, but got %d, %t", model, item, ok)
+			}
+			if ok {
+				model = model[:len(model)-1]
+			}
+		}
+	}
+
+	if got := d.Slice(); d.Len() != len(model) || !reflect.DeepEqual(got, append([]int{}, model...)) {
+		t.Fatalf("expected %v, but got %v", model, got)
+	}
+	for i := range model {
+		if d.At(i) != model[i] {
+			t.Fatalf("at %d: expected %d, but got %d", i, model[i], d.At(i))
+		}
+	}
+}
+
+func TestDequeGrowAndShrink(t *testing.T) {
+	d := NewDeque[int]()
+	for i := 0; i < 1000; i++ {
+		d.PushBack(i)
+	}
+	if len(d.buf) != 1024 {
+		t.Errorf("expected buffer grown to 1024, but got %d", len(d.buf))
+	}
+
+	for i := 0; i < 1000; i++ {
+		if item, _ := d.PopFront(); item != i {
+			t.Fatalf("expected pop %d, but got %d", i, item)
+		}
+	}
+	if len(d.buf) != minDequeCap {
+		t.Errorf("expected buffer shrunk to %d, but got %d", minDequeCap, len(d.buf))
+	}
+
+	q := New(1, 2, 3)
+	for i := 0; i < 100; i++ {
+		q.Push(i)
+		q.Pop()
+	}
+	if q.Len() != 3 || len(q.data.buf) != minDequeCap {
+		t.Errorf("expected queue buffer not grown, but got %d", len(q.data.buf))
+	}
+}
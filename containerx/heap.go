@@ -1,5 +1,7 @@
 package containerx
 
+import "iter"
+
 type Heap[T any] struct {
 	data []T
 	less func(x, y T) bool
@@ -69,6 +71,18 @@ func (h *Heap[T]) Fix(i int) {
 	}
 }
 
+// All returns an iterator over the items in heap order, which is not sorted
+// except the first one, the heap must not be modified during the iteration.
+func (h *Heap[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range h.data {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
 func (h *Heap[T]) Len() int {
 	return len(h.data)
 }
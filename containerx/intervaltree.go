@@ -0,0 +1,222 @@
+package containerx
+
+import (
+	"iter"
+	"math/rand"
+)
+
+// Interval is the closed interval [Start, End].
+type Interval[T any] struct {
+	Start, End T
+}
+
+// IntervalTree stores intervals with values and finds the intervals which
+// contain a point or overlap an interval in O(log n + k), k is the number of
+// results. It is a treap ordered by the start of the intervals, every node
+// keeps the max end of its subtree to skip subtrees which end too early.
+type IntervalTree[T any, V comparable] struct {
+	less func(x, y T) bool
+	root *intervalNode[T, V]
+	size int
+}
+
+type intervalNode[T any, V comparable] struct {
+	interval Interval[T]
+	value    V
+	// maxEnd is the max end of the intervals in the subtree
+	maxEnd      T
+	priority    int64
+	left, right *intervalNode[T, V]
+}
+
+// NewIntervalTree returns an interval tree ordered by less, e.g.
+// func(x, y time.Time) bool { return x.Before(y) } for time intervals.
+func NewIntervalTree[T any, V comparable](less func(x, y T) bool) *IntervalTree[T, V] {
+	return &IntervalTree[T, V]{less: less}
+}
+
+func (t *IntervalTree[T, V]) Len() int {
+	return t.size
+}
+
+// Insert adds the interval with value, the same interval may be added more than once.
+func (t *IntervalTree[T, V]) Insert(interval Interval[T], value V) {
+	if t.less(interval.End, interval.Start) {
+		panic("invaild interval, end is before start")
+	}
+
+	t.root = t.insert(t.root, &intervalNode[T, V]{
+		interval: interval,
+		value:    value,
+		maxEnd:   interval.End,
+		priority: rand.Int63(),
+	})
+	t.size++
+}
+
+func (t *IntervalTree[T, V]) insert(n, node *intervalNode[T, V]) *intervalNode[T, V] {
+	if n == nil {
+		return node
+	}
+
+	if t.compare(node.interval, n.interval) < 0 {
+		n.left = t.insert(n.left, node)
+		if n.left.priority > n.priority {
+			n = t.rotateRight(n)
+		}
+	} else {
+		n.right = t.insert(n.right, node)
+		if n.right.priority > n.priority {
+			n = t.rotateLeft(n)
+		}
+	}
+
+	t.update(n)
+	return n
+}
+
+// Delete removes the interval with value and returns false if it is not found.
+func (t *IntervalTree[T, V]) Delete(interval Interval[T], value V) bool {
+	var deleted bool
+	t.root, deleted = t.delete(t.root, interval, value)
+	if deleted {
+		t.size--
+	}
+	return deleted
+}
+
+func (t *IntervalTree[T, V]) delete(n *intervalNode[T, V], interval Interval[T], value V) (*intervalNode[T, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	var deleted bool
+	switch cmp := t.compare(interval, n.interval); {
+	case cmp < 0:
+		n.left, deleted = t.delete(n.left, interval, value)
+	case cmp > 0:
+		n.right, deleted = t.delete(n.right, interval, value)
+	default:
+		if n.value == value {
+			return t.merge(n.left, n.right), true
+		}
+		// rotations may move equal intervals to both sides
+		if n.left, deleted = t.delete(n.left, interval, value); !deleted {
+			n.right, deleted = t.delete(n.right, interval, value)
+		}
+	}
+
+	if deleted {
+		t.update(n)
+	}
+	return n, deleted
+}
+
+// Stab returns an iterator over the intervals which contain point, ordered
+// by start. The tree must not be modified during the iteration.
+func (t *IntervalTree[T, V]) Stab(point T) iter.Seq2[Interval[T], V] {
+	return t.Overlap(Interval[T]{Start: point, End: point})
+}
+
+// Overlap returns an iterator over the intervals which overlap interval,
+// ordered by start. The tree must not be modified during the iteration.
+func (t *IntervalTree[T, V]) Overlap(interval Interval[T]) iter.Seq2[Interval[T], V] {
+	return func(yield func(Interval[T], V) bool) {
+		t.overlap(t.root, interval, yield)
+	}
+}
+
+func (t *IntervalTree[T, V]) overlap(n *intervalNode[T, V], interval Interval[T], yield func(Interval[T], V) bool) bool {
+	// no interval in the subtree ends after the start
+	if n == nil || t.less(n.maxEnd, interval.Start) {
+		return true
+	}
+
+	if !t.overlap(n.left, interval, yield) {
+		return false
+	}
+
+	// the intervals of n and its right subtree start after the end
+	if t.less(interval.End, n.interval.Start) {
+		return true
+	}
+
+	if !t.less(n.interval.End, interval.Start) && !yield(n.interval, n.value) {
+		return false
+	}
+	return t.overlap(n.right, interval, yield)
+}
+
+// All returns an iterator over the intervals ordered by start.
+func (t *IntervalTree[T, V]) All() iter.Seq2[Interval[T], V] {
+	return func(yield func(Interval[T], V) bool) {
+		t.all(t.root, yield)
+	}
+}
+
+func (t *IntervalTree[T, V]) all(n *intervalNode[T, V], yield func(Interval[T], V) bool) bool {
+	if n == nil {
+		return true
+	}
+	return t.all(n.left, yield) && yield(n.interval, n.value) && t.all(n.right, yield)
+}
+
+// compare orders intervals by start, then by end.
+func (t *IntervalTree[T, V]) compare(x, y Interval[T]) int {
+	switch {
+	case t.less(x.Start, y.Start):
+		return -1
+	case t.less(y.Start, x.Start):
+		return 1
+	case t.less(x.End, y.End):
+		return -1
+	case t.less(y.End, x.End):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// merge joins two treaps, the intervals of x are not greater than those of y.
+func (t *IntervalTree[T, V]) merge(x, y *intervalNode[T, V]) *intervalNode[T, V] {
+	switch {
+	case x == nil:
+		return y
+	case y == nil:
+		return x
+	case x.priority > y.priority:
+		x.right = t.merge(x.right, y)
+		t.update(x)
+		return x
+	default:
+		y.left = t.merge(x, y.left)
+		t.update(y)
+		return y
+	}
+}
+
+func (t *IntervalTree[T, V]) rotateRight(n *intervalNode[T, V]) *intervalNode[T, V] {
+	l := n.left
+	n.left, l.right = l.right, n
+	t.update(n)
+	t.update(l)
+	return l
+}
+
+func (t *IntervalTree[T, V]) rotateLeft(n *intervalNode[T, V]) *intervalNode[T, V] {
+	r := n.right
+	n.right, r.left = r.left, n
+	t.update(n)
+	t.update(r)
+	return r
+}
+
+func (t *IntervalTree[T, V]) update(n *intervalNode[T, V]) {
+	n.maxEnd = n.interval.End
+	if n.left != nil && t.less(n.maxEnd, n.left.maxEnd) {
+		n.maxEnd = n.left.maxEnd
+	}
+	if n.right != nil && t.less(n.maxEnd, n.right.maxEnd) {
+		n.maxEnd = n.right.maxEnd
+	}
+}
@@ -0,0 +1,133 @@
+package containerx
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestIntervalTree(t *testing.T) {
+	tree := NewIntervalTree[int, int](func(x, y int) bool { return x < y })
+
+	type entry struct {
+		interval Interval[int]
+		value    int
+	}
+	var entries []entry
+	for i := 0; i < 2000; i++ {
+		if len(entries) > 0 && rand.Intn(3) == 0 {
+			j := rand.Intn(len(entries))
+			if !tree.Delete(entries[j].interval, entries[j].value) {
+				t.Fatalf("expected %v deleted", entries[j])
+			}
+			entries = append(entries[:j], entries[j+1:]...)
+			continue
+		}
+
+		start := rand.Intn(1000)
+		e := entry{Interval[int]{start, start + rand.Intn(50)}, rand.Intn(3)}
+		tree.Insert(e.interval, e.value)
+		entries = append(entries, e)
+	}
+
+	if tree.Len() != len(entries) {
+		t.Fatalf("expected len %d, but got %d", len(entries), tree.Len())
+	}
+	if tree.Delete(Interval[int]{-2, -1}, 0) {
+		t.Errorf("expected absent interval not deleted")
+	}
+
+	sortEntries := func(entries []entry) {
+		sort.Slice(entries, func(i, j int) bool {
+			x, y := entries[i], entries[j]
+			if x.interval != y.interval {
+				return x.interval.Start < y.interval.Start ||
+					x.interval.Start == y.interval.Start && x.interval.End < y.interval.End
+			}
+			return x.value < y.value
+		})
+	}
+
+	for i := 0; i < 200; i++ {
+		start := rand.Intn(1100) - 50
+		query := Interval[int]{start, start + rand.Intn(30)}
+
+		expected := []entry{}
+		for _, e := range entries {
+			if e.interval.Start <= query.End && query.Start <= e.interval.End {
+				expected = append(expected, e)
+			}
+		}
+
+		got := []entry{}
+		last := -1 << 31
+		for interval, value := range tree.Overlap(query) {
+			if interval.Start < last {
+				t.Fatalf("expected overlaps ordered by start")
+			}
+			last = interval.Start
+			got = append(got, entry{interval, value})
+		}
+
+		sortEntries(expected)
+		sortEntries(got)
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("overlap %v: expected %v, but got %v", query, expected, got)
+		}
+	}
+
+	count := 0
+	for range tree.All() {
+		count++
+	}
+	if count != len(entries) {
+		t.Errorf("expected %d intervals, but got %d", len(entries), count)
+	}
+}
+
+func TestIntervalTreeTime(t *testing.T) {
+	tree := NewIntervalTree[time.Time, string](func(x, y time.Time) bool { return x.Before(y) })
+	at := func(hour int) time.Time {
+		return time.Date(2023, 1, 1, hour, 0, 0, 0, time.UTC)
+	}
+
+	tree.Insert(Interval[time.Time]{at(1), at(3)}, "db")
+	tree.Insert(Interval[time.Time]{at(2), at(5)}, "network")
+	tree.Insert(Interval[time.Time]{at(6), at(7)}, "deploy")
+
+	stab := func(point time.Time) []string {
+		var got []string
+		for _, window := range tree.Stab(point) {
+			got = append(got, window)
+		}
+		return got
+	}
+
+	if got := stab(at(3)); !reflect.DeepEqual(got, []string{"db", "network"}) {
+		t.Errorf("expected windows db and network at 3:00, but got %v", got)
+	}
+	if got := stab(at(5).Add(time.Minute)); got != nil {
+		t.Errorf("expected no window at 5:01, but got %v", got)
+	}
+
+	// stop early
+	for range tree.Overlap(Interval[time.Time]{at(0), at(8)}) {
+		break
+	}
+
+	if !tree.Delete(Interval[time.Time]{at(1), at(3)}, "db") || tree.Len() != 2 {
+		t.Errorf("expected db window deleted")
+	}
+	if got := stab(at(3)); !reflect.DeepEqual(got, []string{"network"}) {
+		t.Errorf("expected window network at 3:00, but got %v", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic on invalid interval")
+		}
+	}()
+	tree.Insert(Interval[time.Time]{at(2), at(1)}, "invalid")
+}
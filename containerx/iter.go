@@ -0,0 +1,43 @@
+package containerx
+
+import "iter"
+
+// Map returns an iterator over f applied to the items of seq.
+func Map[T, U any](seq iter.Seq[T], f func(T) U) iter.Seq[U] {
+	return func(yield func(U) bool) {
+		for item := range seq {
+			if !yield(f(item)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns an iterator over the items of seq for which pred returns true.
+func Filter[T any](seq iter.Seq[T], pred func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for item := range seq {
+			if pred(item) && !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Reduce folds the items of seq into an accumulator starting from init.
+func Reduce[T, U any](seq iter.Seq[T], init U, f func(U, T) U) U {
+	acc := init
+	for item := range seq {
+		acc = f(acc, item)
+	}
+	return acc
+}
+
+// Collect returns the items of seq in a slice.
+func Collect[T any](seq iter.Seq[T]) []T {
+	var items []T
+	for item := range seq {
+		items = append(items, item)
+	}
+	return items
+}
@@ -0,0 +1,175 @@
+package containerx
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"sort"
+	"testing"
+	"testing/quick"
+)
+
+// ringModel applies ops to a ring and a slice model, an op is a push back,
+// push front, pop back or pop front chosen by its value.
+func ringModel(size int, ops []int16) (*Ring[int16], []int16) {
+	r := NewRing[int16](size)
+	var model []int16
+	for _, op := range ops {
+		switch op & 3 {
+		case 0:
+			r.PushBack(op)
+			model = append(model, op)
+			if len(model) > size {
+				model = model[1:]
+			}
+		case 1:
+			r.PushFront(op)
+			model = append([]int16{op}, model...)
+			if len(model) > size {
+				model = model[:size]
+			}
+		case 2:
+			r.PopBack()
+			if len(model) > 0 {
+				model = model[:len(model)-1]
+			}
+		case 3:
+			r.PopFront()
+			if len(model) > 0 {
+				model = model[1:]
+			}
+		}
+	}
+	return r, model
+}
+
+func TestRingProperties(t *testing.T) {
+	config := &quick.Config{MaxCount: 1000}
+
+	all := func(size uint8, ops []int16) bool {
+		r, model := ringModel(int(size%16)+1, ops)
+		got := Collect(r.All())
+		return r.Len() == len(model) && (len(model) == 0 && got == nil || reflect.DeepEqual(got, model))
+	}
+	if err := quick.Check(all, config); err != nil {
+		t.Errorf("expected ring elements equal to the model: %v", err)
+	}
+
+	frontBack := func(size uint8, ops []int16) bool {
+		r, model := ringModel(int(size%16)+1, ops)
+		front, okFront := r.Front()
+		back, okBack := r.Back()
+		if len(model) == 0 {
+			return !okFront && !okBack && front == 0 && back == 0
+		}
+		return okFront && okBack && front == model[0] && back == model[len(model)-1]
+	}
+	if err := quick.Check(frontBack, config); err != nil {
+		t.Errorf("expected ring front and back equal to the model: %v", err)
+	}
+
+	earlyStop := func(size uint8, ops []int16, stop uint8) bool {
+		r, model := ringModel(int(size%16)+1, ops)
+		n := 0
+		if len(model) > 0 {
+			n = int(stop) % len(model)
+		}
+
+		var got []int16
+		r.Range(func(elem int16) bool {
+			got = append(got, elem)
+			return len(got) <= n
+		})
+		if len(model) == 0 {
+			return got == nil
+		}
+		return reflect.DeepEqual(got, model[:n+1])
+	}
+	if err := quick.Check(earlyStop, config); err != nil {
+		t.Errorf("expected range stopped early: %v", err)
+	}
+}
+
+func TestAll(t *testing.T) {
+	items := rand.Perm(100)
+	sorted := slices.Clone(items)
+	sort.Ints(sorted)
+
+	less := func(x, y int) bool { return x < y }
+	pq := NewPriorityQueue[int](less)
+	d := NewDeque[int]()
+	bq := NewBlockingQueue[int](len(items))
+	sr := NewSyncRing[int](len(items))
+	for _, item := range items {
+		pq.Push(item, item)
+		d.PushBack(item)
+		bq.Push(item)
+		sr.PushBack(item)
+	}
+
+	tests := []struct {
+		name    string
+		got     []int
+		ordered bool
+	}{
+		{"set", Collect(NewSet(items...).All()), false},
+		{"heap", Collect(NewHeap(slices.Clone(items), less).All()), false},
+		{"queue", Collect(New(items...).All()), true},
+		{"deque", Collect(d.All()), true},
+		{"ring", Collect(sr.All()), true},
+		{"blocking queue", Collect(bq.All()), true},
+		{"sync queue", Collect(NewSyncQueue(items...).All()), true},
+		{"sync set", Collect(NewSyncSet(items...).All()), false},
+		{"sync heap", Collect(NewSyncHeap(slices.Clone(items), less).All()), false},
+	}
+
+	keys := []int{}
+	for k, v := range pq.All() {
+		if k != v {
+			t.Errorf("priority queue: expected key %d equal to value %d", k, v)
+		}
+		keys = append(keys, k)
+	}
+	tests = append(tests, struct {
+		name    string
+		got     []int
+		ordered bool
+	}{"priority queue", keys, false})
+
+	for _, test := range tests {
+		expected := items
+		if !test.ordered {
+			sort.Ints(test.got)
+			expected = sorted
+		}
+		if !reflect.DeepEqual(test.got, expected) {
+			t.Errorf("%s: expected %v, but got %v", test.name, expected, test.got)
+		}
+	}
+}
+
+func TestFunctionalHelpers(t *testing.T) {
+	q := New(1, 2, 3, 4, 5, 6)
+
+	even := Filter(q.All(), func(x int) bool { return x%2 == 0 })
+	squares := Collect(Map(even, func(x int) int { return x * x }))
+	if !reflect.DeepEqual(squares, []int{4, 16, 36}) {
+		t.Errorf("expected squares of even items, but got %v", squares)
+	}
+
+	sum := Reduce(NewSet(1, 2, 3).All(), 0, func(acc, x int) int { return acc + x })
+	if sum != 6 {
+		t.Errorf("expected sum 6, but got %d", sum)
+	}
+
+	// stop early through the helpers
+	for x := range Map(q.All(), func(x int) int { return x * 10 }) {
+		if x > 20 {
+			break
+		}
+	}
+
+	if got := Collect(Filter(q.All(), func(int) bool { return false })); got != nil {
+		t.Errorf("expected nothing collected, but got %v", got)
+	}
+}
@@ -0,0 +1,131 @@
+package containerx
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LRUCache is a cache of bounded size safe for concurrent use, it evicts
+// the least recently used entry when it is full.
+type LRUCache[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[K]*list.Element
+	// order is from the most recently used to the least
+	order   *list.List
+	onEvict func(key K, value V)
+	stats   CacheStats
+}
+
+// NewLRUCache returns a cache holding up to capacity entries, onEvict is
+// called with every evicted entry if it is not nil, outside of the lock.
+func NewLRUCache[K comparable, V any](capacity int, onEvict func(key K, value V)) *LRUCache[K, V] {
+	if capacity <= 0 {
+		panic("invaild cache capacity")
+	}
+
+	return &LRUCache[K, V]{
+		capacity: capacity,
+		entries:  make(map[K]*list.Element),
+		order:    list.New(),
+		onEvict:  onEvict,
+	}
+}
+
+// Add adds or updates the entry and marks it as most recently used, it
+// returns true if an entry is evicted.
+func (c *LRUCache[K, V]) Add(key K, value V) bool {
+	c.mu.Lock()
+
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*mapEntry[K, V]).value = value
+		c.order.MoveToFront(e)
+		c.mu.Unlock()
+		return false
+	}
+
+	c.entries[key] = c.order.PushFront(&mapEntry[K, V]{key: key, value: value})
+	if c.order.Len() <= c.capacity {
+		c.mu.Unlock()
+		return false
+	}
+
+	evicted := c.removeElement(c.order.Back())
+	c.stats.Evictions++
+	c.mu.Unlock()
+
+	if c.onEvict != nil {
+		c.onEvict(evicted.key, evicted.value)
+	}
+	return true
+}
+
+// Get returns the value of key and marks it as most recently used.
+func (c *LRUCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	c.stats.record(ok)
+	if !ok {
+		var v V
+		return v, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*mapEntry[K, V]).value, true
+}
+
+// Peek returns the value of key without marking it or counting the lookup.
+func (c *LRUCache[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		var v V
+		return v, false
+	}
+	return e.Value.(*mapEntry[K, V]).value, true
+}
+
+// Remove removes the entry without calling onEvict.
+func (c *LRUCache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if ok {
+		c.removeElement(e)
+	}
+	return ok
+}
+
+// Keys returns the keys from the most recently used to the least.
+func (c *LRUCache[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]K, 0, c.order.Len())
+	for e := c.order.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*mapEntry[K, V]).key)
+	}
+	return keys
+}
+
+func (c *LRUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRUCache[K, V]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *LRUCache[K, V]) removeElement(e *list.Element) *mapEntry[K, V] {
+	entry := c.order.Remove(e).(*mapEntry[K, V])
+	delete(c.entries, entry.key)
+	return entry
+}
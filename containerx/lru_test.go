@@ -0,0 +1,68 @@
+package containerx
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestLRUCache(t *testing.T) {
+	var evicted []string
+	c := NewLRUCache(2, func(key string, value int) {
+		evicted = append(evicted, key)
+	})
+
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Get("a")
+	if !c.Add("c", 3) {
+		t.Errorf("expected an entry evicted")
+	}
+	if !reflect.DeepEqual(evicted, []string{"b"}) {
+		t.Errorf("expected the least recently used b evicted, but got %v", evicted)
+	}
+	if keys := c.Keys(); !reflect.DeepEqual(keys, []string{"c", "a"}) {
+		t.Errorf("expected keys [c a], but got %v", keys)
+	}
+
+	// peek does not change the order
+	c.Peek("a")
+	c.Add("d", 4)
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("expected a evicted")
+	}
+	if c.Add("d", 5) {
+		t.Errorf("expected no eviction on update")
+	}
+	if value, _ := c.Get("d"); value != 5 {
+		t.Errorf("expected d updated to 5, but got %d", value)
+	}
+
+	if !c.Remove("c") || c.Remove("c") || c.Len() != 1 {
+		t.Errorf("expected c removed")
+	}
+	if stats := c.Stats(); stats.Hits != 2 || stats.Misses != 1 || stats.Evictions != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestLRUCacheConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	evicted := 0
+	c := NewLRUCache(100, func(int, int) {
+		mu.Lock()
+		evicted++
+		mu.Unlock()
+	})
+
+	parallel(func(worker int) {
+		for i := 0; i < perWork; i++ {
+			c.Add(worker*perWork+i, i)
+			c.Get(i)
+		}
+	})
+
+	if c.Len() != 100 || evicted != workers*perWork-100 || c.Stats().Evictions != uint64(evicted) {
+		t.Errorf("expected %d entries evicted, but got %d, len %d", workers*perWork-100, evicted, c.Len())
+	}
+}
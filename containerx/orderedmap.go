@@ -0,0 +1,105 @@
+package containerx
+
+import (
+	"container/list"
+	"iter"
+)
+
+// OrderedMap is a map which iterates in insertion order, setting an
+// existing key keeps its position.
+type OrderedMap[K comparable, V any] struct {
+	entries map[K]*list.Element
+	order   *list.List
+	stats   CacheStats
+}
+
+type mapEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{
+		entries: make(map[K]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if e, ok := m.entries[key]; ok {
+		e.Value.(*mapEntry[K, V]).value = value
+		return
+	}
+	m.entries[key] = m.order.PushBack(&mapEntry[K, V]{key: key, value: value})
+}
+
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	e, ok := m.entries[key]
+	m.stats.record(ok)
+	if !ok {
+		var v V
+		return v, false
+	}
+	return e.Value.(*mapEntry[K, V]).value, true
+}
+
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := m.entries[key]
+	return ok
+}
+
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	e, ok := m.entries[key]
+	if !ok {
+		return false
+	}
+	m.order.Remove(e)
+	delete(m.entries, key)
+	return true
+}
+
+// Oldest returns the first inserted entry.
+func (m *OrderedMap[K, V]) Oldest() (K, V, bool) {
+	e := m.order.Front()
+	if e == nil {
+		var (
+			k K
+			v V
+		)
+		return k, v, false
+	}
+	entry := e.Value.(*mapEntry[K, V])
+	return entry.key, entry.value, true
+}
+
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.entries)
+}
+
+func (m *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, m.Len())
+	for key := range m.All() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// All returns an iterator over the entries in insertion order, the current
+// entry may be deleted during the iteration.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for e := m.order.Front(); e != nil; {
+			next := e.Next()
+			entry := e.Value.(*mapEntry[K, V])
+			if !yield(entry.key, entry.value) {
+				return
+			}
+			e = next
+		}
+	}
+}
+
+// Stats returns the hits and misses of Get.
+func (m *OrderedMap[K, V]) Stats() CacheStats {
+	return m.stats
+}
@@ -0,0 +1,40 @@
+package containerx
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	m.Set("c", 1)
+	m.Set("a", 2)
+	m.Set("b", 3)
+	m.Set("c", 4)
+
+	if keys := m.Keys(); !reflect.DeepEqual(keys, []string{"c", "a", "b"}) {
+		t.Errorf("expected keys in insertion order, but got %v", keys)
+	}
+	if value, ok := m.Get("c"); !ok || value != 4 {
+		t.Errorf("expected c updated to 4, but got %d", value)
+	}
+	if _, ok := m.Get("d"); ok {
+		t.Errorf("expected no d")
+	}
+	if stats := m.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.HitRate() != 0.5 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// delete during iteration
+	for key := range m.All() {
+		if key == "a" {
+			m.Delete(key)
+		}
+	}
+	if m.Delete("a") || m.Len() != 2 || m.Has("a") {
+		t.Errorf("expected a deleted")
+	}
+	if key, value, ok := m.Oldest(); !ok || key != "c" || value != 4 {
+		t.Errorf("expected oldest c:4, but got %s:%d", key, value)
+	}
+}
@@ -0,0 +1,161 @@
+package containerx
+
+import (
+	"container/heap"
+	"iter"
+)
+
+// PriorityQueue is a heap of values indexed by key, so a value can be
+// updated or removed by its key in O(log n).
+type PriorityQueue[K comparable, V any] struct {
+	items *pqItems[K, V]
+	index map[K]*pqItem[K, V]
+}
+
+type pqItem[K comparable, V any] struct {
+	key   K
+	value V
+	// index is the position of the item in the heap
+	index int
+}
+
+type pqItems[K comparable, V any] struct {
+	data []*pqItem[K, V]
+	less func(x, y V) bool
+}
+
+func NewPriorityQueue[K comparable, V any](less func(x, y V) bool) *PriorityQueue[K, V] {
+	return &PriorityQueue[K, V]{
+		items: &pqItems[K, V]{less: less},
+		index: make(map[K]*pqItem[K, V]),
+	}
+}
+
+// Push adds the value with key, it updates the value if key exists.
+func (pq *PriorityQueue[K, V]) Push(key K, value V) {
+	if pq.Update(key, value) {
+		return
+	}
+
+	item := &pqItem[K, V]{key: key, value: value}
+	pq.index[key] = item
+	heap.Push(pq.items, item)
+}
+
+// Update replaces the value of key and returns false if key does not exist.
+func (pq *PriorityQueue[K, V]) Update(key K, value V) bool {
+	item, ok := pq.index[key]
+	if !ok {
+		return false
+	}
+
+	item.value = value
+	heap.Fix(pq.items, item.index)
+	return true
+}
+
+// Remove removes key and returns its value.
+func (pq *PriorityQueue[K, V]) Remove(key K) (V, bool) {
+	item, ok := pq.index[key]
+	if !ok {
+		var v V
+		return v, false
+	}
+
+	heap.Remove(pq.items, item.index)
+	delete(pq.index, key)
+	return item.value, true
+}
+
+func (pq *PriorityQueue[K, V]) Contains(key K) bool {
+	_, ok := pq.index[key]
+	return ok
+}
+
+func (pq *PriorityQueue[K, V]) Get(key K) (V, bool) {
+	item, ok := pq.index[key]
+	if !ok {
+		var v V
+		return v, false
+	}
+	return item.value, true
+}
+
+// PeekMin returns the least value and its key without removing it.
+func (pq *PriorityQueue[K, V]) PeekMin() (K, V, bool) {
+	if pq.Len() == 0 {
+		var (
+			k K
+			v V
+		)
+		return k, v, false
+	}
+
+	item := pq.items.data[0]
+	return item.key, item.value, true
+}
+
+// PopMin removes and returns the least value and its key.
+func (pq *PriorityQueue[K, V]) PopMin() (K, V, bool) {
+	if pq.Len() == 0 {
+		var (
+			k K
+			v V
+		)
+		return k, v, false
+	}
+
+	item := heap.Pop(pq.items).(*pqItem[K, V])
+	delete(pq.index, item.key)
+	return item.key, item.value, true
+}
+
+// Range calls f for every key and value in arbitrary order until f returns false.
+func (pq *PriorityQueue[K, V]) Range(f func(K, V) bool) {
+	for _, item := range pq.items.data {
+		if !f(item.key, item.value) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the keys and values in arbitrary order.
+func (pq *PriorityQueue[K, V]) All() iter.Seq2[K, V] {
+	return pq.Range
+}
+
+func (pq *PriorityQueue[K, V]) Len() int {
+	return len(pq.items.data)
+}
+
+func (pq *PriorityQueue[K, V]) Empty() bool {
+	return pq.Len() == 0
+}
+
+func (h *pqItems[K, V]) Len() int {
+	return len(h.data)
+}
+
+func (h *pqItems[K, V]) Less(i, j int) bool {
+	return h.less(h.data[i].value, h.data[j].value)
+}
+
+func (h *pqItems[K, V]) Swap(i, j int) {
+	h.data[i], h.data[j] = h.data[j], h.data[i]
+	h.data[i].index = i
+	h.data[j].index = j
+}
+
+func (h *pqItems[K, V]) Push(x any) {
+	item := x.(*pqItem[K, V])
+	item.index = len(h.data)
+	h.data = append(h.data, item)
+}
+
+func (h *pqItems[K, V]) Pop() any {
+	n := len(h.data) - 1
+	item := h.data[n]
+	h.data[n] = nil
+	h.data = h.data[:n]
+	return item
+}
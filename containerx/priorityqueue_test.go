@@ -0,0 +1,83 @@
+package containerx
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	pq := NewPriorityQueue[string](func(x, y int) bool { return x < y })
+	pq.Push("a", 5)
+	pq.Push("b", 3)
+	pq.Push("c", 8)
+	pq.Push("d", 1)
+
+	if !pq.Contains("a") || pq.Contains("e") || pq.Len() != 4 {
+		t.Errorf("contains unexpected")
+	}
+
+	if key, value, ok := pq.PeekMin(); !ok || key != "d" || value != 1 {
+		t.Errorf("expected min d:1, but got %s:%d", key, value)
+	}
+
+	if !pq.Update("c", 0) || pq.Update("e", 0) {
+		t.Errorf("update unexpected")
+	}
+	// push an existing key updates it
+	pq.Push("d", 10)
+	if pq.Len() != 4 {
+		t.Errorf("expected len 4, but got %d", pq.Len())
+	}
+
+	if value, ok := pq.Remove("b"); !ok || value != 3 {
+		t.Errorf("expected removed b:3, but got %d", value)
+	}
+	if _, ok := pq.Remove("b"); ok {
+		t.Errorf("expected b already removed")
+	}
+
+	expected := []string{"c", "a", "d"}
+	for _, key := range expected {
+		got, _, ok := pq.PopMin()
+		if !ok || got != key {
+			t.Errorf("expected pop %s, but got %s", key, got)
+		}
+	}
+	if _, _, ok := pq.PopMin(); ok || !pq.Empty() {
+		t.Errorf("expected empty queue")
+	}
+}
+
+func TestPriorityQueueRandom(t *testing.T) {
+	pq := NewPriorityQueue[int](func(x, y int) bool { return x < y })
+	values := make(map[int]int)
+	for i := 0; i < 1000; i++ {
+		key := rand.Intn(200)
+		switch rand.Intn(3) {
+		case 0, 1:
+			value := rand.Intn(10000)
+			pq.Push(key, value)
+			values[key] = value
+		case 2:
+			_, ok := pq.Remove(key)
+			if _, exists := values[key]; exists != ok {
+				t.Fatalf("remove %d: expected %t, but got %t", key, exists, ok)
+			}
+			delete(values, key)
+		}
+	}
+
+	expected := make([]int, 0, len(values))
+	for _, value := range values {
+		expected = append(expected, value)
+	}
+	sort.Ints(expected)
+
+	for i, value := range expected {
+		key, got, ok := pq.PopMin()
+		if !ok || got != value || values[key] != got {
+			t.Fatalf("pop %d: expected %d, but got %d:%d", i, value, key, got)
+		}
+	}
+}
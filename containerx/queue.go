@@ -1,53 +1,40 @@
 package containerx
 
+import "iter"
+
 func New[T any](items ...T) *Queue[T] {
-	return &Queue[T]{items}
+	return &Queue[T]{data: *NewDeque(items...)}
 }
 
+// Queue is a FIFO queue backed by Deque, so its buffer shrinks as items
+// are popped.
 type Queue[T any] struct {
-	data []T
+	data Deque[T]
 }
 
 func (q *Queue[T]) Push(item T) {
-	q.data = append(q.data, item)
+	q.data.PushBack(item)
 }
 
 func (q *Queue[T]) Pop() (T, bool) {
-	var t T
-	if q.Empty() {
-		return t, false
-	}
-
-	item := q.data[0]
-	q.data = q.data[1:]
-
-	return item, true
+	return q.data.PopFront()
 }
 
 func (q *Queue[T]) Front() (T, bool) {
-	var t T
-	if q.Empty() {
-		return t, false
-	}
-
-	item := q.data[0]
-
-	return item, true
+	return q.data.Front()
 }
 
 func (q *Queue[T]) Back() (T, bool) {
-	var t T
-	if q.Empty() {
-		return t, false
-	}
-
-	item := q.data[q.Len()-1]
+	return q.data.Back()
+}
 
-	return item, true
+// All returns an iterator over the items from the front.
+func (q *Queue[T]) All() iter.Seq[T] {
+	return q.data.All()
 }
 
 func (q *Queue[T]) Len() int {
-	return len(q.data)
+	return q.data.Len()
 }
 
 func (q *Queue[T]) Empty() bool {
@@ -1,18 +1,59 @@
 package containerx
 
+import (
+	"errors"
+	"iter"
+)
+
+var ErrRingFull = errors.New("ring is full")
+
+// RingPolicy decides what a push does when the ring is full.
+type RingPolicy int
+
+const (
+	// RingOverwrite overwrites the element at the other end, so the ring
+	// keeps the latest elements pushed back, it is the default.
+	RingOverwrite RingPolicy = iota
+	// RingReject rejects the push with ErrRingFull.
+	RingReject
+	// RingBlock blocks the push until there is room, only SyncRing can
+	// block, Ring rejects the push like RingReject.
+	RingBlock
+)
+
+type RingOption func(*ringOption)
+
+type ringOption struct {
+	policy RingPolicy
+}
+
+func WithRingPolicy(policy RingPolicy) RingOption {
+	return func(o *ringOption) {
+		o.policy = policy
+	}
+}
+
 type Ring[T any] struct {
 	size              int
 	elems             []T
 	head, tail, count int
+	policy            RingPolicy
 }
 
-func NewRing[T any](size int) *Ring[T] {
+func NewRing[T any](size int, opts ...RingOption) *Ring[T] {
 	if size <= 0 {
 		panic("invaild ring size")
 	}
+
+	option := &ringOption{}
+	for _, opt := range opts {
+		opt(option)
+	}
+
 	return &Ring[T]{
-		elems: make([]T, size),
-		size:  size,
+		elems:  make([]T, size),
+		size:   size,
+		policy: option.policy,
 	}
 }
 
@@ -20,25 +61,38 @@ func (r *Ring[T]) Len() int {
 	return r.count
 }
 
-func (r *Ring[T]) PushFront(elems ...T) {
+func (r *Ring[T]) Cap() int {
+	return r.size
+}
+
+// PushFront pushes the elements to the front one by one. Unless the policy
+// is RingOverwrite, it returns ErrRingFull and pushes nothing if there is
+// no room for all of them.
+func (r *Ring[T]) PushFront(elems ...T) error {
+	if err := r.room(len(elems)); err != nil {
+		return err
+	}
+
 	for i := range elems {
-		if r.count == 0 {
-			r.elems[r.head] = elems[i]
-			r.head = r.prevHead()
-		} else {
-			r.head = r.prevHead()
-			r.elems[r.head] = elems[i]
-		}
+		r.head = r.prevHead()
+		r.elems[r.head] = elems[i]
 
 		if r.count == r.size {
-			r.tail = r.prevHead()
+			// overwrite the back
+			r.tail = r.head
 		} else {
 			r.count++
 		}
 	}
+	return nil
 }
 
-func (r *Ring[T]) PushBack(elems ...T) {
+// PushBack pushes the elements to the back, see PushFront.
+func (r *Ring[T]) PushBack(elems ...T) error {
+	if err := r.room(len(elems)); err != nil {
+		return err
+	}
+
 	for i := range elems {
 		r.elems[r.tail] = elems[i]
 		r.tail = r.nextTail()
@@ -49,14 +103,67 @@ func (r *Ring[T]) PushBack(elems ...T) {
 			r.count++
 		}
 	}
+	return nil
+}
+
+func (r *Ring[T]) room(n int) error {
+	if r.policy != RingOverwrite && r.count+n > r.size {
+		return ErrRingFull
+	}
+	return nil
+}
+
+func (r *Ring[T]) Front() (T, bool) {
+	if r.count == 0 {
+		var t T
+		return t, false
+	}
+	return r.elems[r.head], true
 }
 
-func (r *Ring[T]) Front() T {
-	return r.elems[r.head]
+func (r *Ring[T]) Back() (T, bool) {
+	if r.count == 0 {
+		var t T
+		return t, false
+	}
+	return r.elems[r.prevTail()], true
 }
 
-func (r *Ring[T]) Back() T {
-	return r.elems[r.prevTail()]
+// At returns the i-th element from the front, it panics if i is out of range.
+func (r *Ring[T]) At(i int) T {
+	if i < 0 || i >= r.count {
+		panic("ring index out of range")
+	}
+	return r.elems[(r.head+i)%r.size]
+}
+
+// Snapshot returns the elements from the front to the back.
+func (r *Ring[T]) Snapshot() []T {
+	elems := make([]T, 0, r.count)
+	for elem := range r.All() {
+		elems = append(elems, elem)
+	}
+	return elems
+}
+
+// Resize changes the size of the ring, it keeps the elements at the back
+// if the ring shrinks below its length.
+func (r *Ring[T]) Resize(size int) {
+	if size <= 0 {
+		panic("invaild ring size")
+	}
+
+	elems := r.Snapshot()
+	if len(elems) > size {
+		elems = elems[len(elems)-size:]
+	}
+
+	r.elems = make([]T, size)
+	copy(r.elems, elems)
+	r.size = size
+	r.head = 0
+	r.count = len(elems)
+	r.tail = r.count % size
 }
 
 func (r *Ring[T]) PopFront() (T, bool) {
@@ -90,12 +197,26 @@ func (r *Ring[T]) PopBack() (T, bool) {
 	return ret, true
 }
 
-func (r *Ring[T]) Range(f func(T)) {
+// Range calls f for every element from the front until f returns false.
+func (r *Ring[T]) Range(f func(T) bool) {
 	for count := 0; count < r.count; count++ {
-		f(r.elems[(r.head+count)%(r.size-1)])
+		if !f(r.elems[(r.head+count)%r.size]) {
+			return
+		}
 	}
 }
 
+// All returns an iterator over the elements from the front.
+func (r *Ring[T]) All() iter.Seq[T] {
+	return r.Range
+}
+
+// Clear removes all elements.
+func (r *Ring[T]) Clear() {
+	clear(r.elems)
+	r.head, r.tail, r.count = 0, 0, 0
+}
+
 func (r *Ring[T]) nextTail() int {
 	return (r.tail + 1) % r.size
 }
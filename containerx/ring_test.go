@@ -1,6 +1,9 @@
 package containerx
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestRing(t *testing.T) {
 	r := NewRing[int](5)
@@ -15,12 +18,12 @@ func TestRing(t *testing.T) {
 		t.Errorf("expect ring len %d, but got %d", 5, r.Len())
 	}
 
-	if r.Front() != 2 {
-		t.Errorf("expect ring front is %d, but got %d", 2, r.Front())
+	if front, _ := r.Front(); front != 2 {
+		t.Errorf("expect ring front is %d, but got %d", 2, front)
 	}
 
-	if r.Back() != 6 {
-		t.Errorf("expect ring back is %d, but got %d", 6, r.Back())
+	if back, _ := r.Back(); back != 6 {
+		t.Errorf("expect ring back is %d, but got %d", 6, back)
 	}
 
 	r.PopBack()
@@ -29,3 +32,98 @@ func TestRing(t *testing.T) {
 		t.Errorf("expect ring pop back is %d, but got %d", 5, got)
 	}
 }
+
+func TestRingPolicy(t *testing.T) {
+	r := NewRing[int](3, WithRingPolicy(RingReject))
+	if _, ok := r.Front(); ok {
+		t.Errorf("expect no front of empty ring")
+	}
+	if _, ok := r.Back(); ok {
+		t.Errorf("expect no back of empty ring")
+	}
+
+	if err := r.PushBack(1, 2); err != nil {
+		t.Errorf("expect push succeed, but got %v", err)
+	}
+	if err := r.PushFront(0, -1); err != ErrRingFull {
+		t.Errorf("expect %v, but got %v", ErrRingFull, err)
+	}
+	if err := r.PushFront(0); err != nil {
+		t.Errorf("expect push succeed, but got %v", err)
+	}
+	if err := r.PushBack(3); err != ErrRingFull {
+		t.Errorf("expect %v, but got %v", ErrRingFull, err)
+	}
+	if snapshot := r.Snapshot(); !reflect.DeepEqual(snapshot, []int{0, 1, 2}) {
+		t.Errorf("expect ring [0 1 2], but got %v", snapshot)
+	}
+
+	overwrite := NewRing[int](3)
+	overwrite.PushBack(1, 2, 3, 4)
+	overwrite.PushFront(0)
+	if snapshot := overwrite.Snapshot(); !reflect.DeepEqual(snapshot, []int{0, 2, 3}) {
+		t.Errorf("expect ring [0 2 3], but got %v", snapshot)
+	}
+}
+
+func TestRingAtAndResize(t *testing.T) {
+	r := NewRing[int](4)
+	r.PushBack(1, 2, 3, 4, 5, 6)
+	for i, expected := range []int{3, 4, 5, 6} {
+		if got := r.At(i); got != expected {
+			t.Errorf("expect ring at %d is %d, but got %d", i, expected, got)
+		}
+	}
+
+	r.Resize(2)
+	if snapshot := r.Snapshot(); !reflect.DeepEqual(snapshot, []int{5, 6}) || r.Cap() != 2 {
+		t.Errorf("expect ring shrunk to [5 6], but got %v", snapshot)
+	}
+
+	r.Resize(4)
+	r.PushBack(7, 8)
+	if snapshot := r.Snapshot(); !reflect.DeepEqual(snapshot, []int{5, 6, 7, 8}) {
+		t.Errorf("expect ring grown to [5 6 7 8], but got %v", snapshot)
+	}
+	r.PushBack(9)
+	if front, _ := r.Front(); front != 6 {
+		t.Errorf("expect ring front is 6, but got %d", front)
+	}
+
+	r.Clear()
+	if r.Len() != 0 || len(r.Snapshot()) != 0 {
+		t.Errorf("expect ring cleared")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expect panic out of range")
+		}
+	}()
+	r.At(0)
+}
+
+func TestRingAggregates(t *testing.T) {
+	// a sliding window of the latest 10 latencies in milliseconds
+	r := NewRing[float64](10)
+	for i := 1; i <= 20; i++ {
+		r.PushBack(float64(i))
+	}
+
+	if sum := Sum(r.All()); sum != 155 {
+		t.Errorf("expect sum 155, but got %v", sum)
+	}
+	if mean := Mean(r.All()); mean != 15.5 {
+		t.Errorf("expect mean 15.5, but got %v", mean)
+	}
+	for p, expected := range map[float64]float64{0: 11, 50: 15, 90: 19, 99: 20, 100: 20} {
+		if got := Percentile(r.All(), p); got != expected {
+			t.Errorf("expect p%v %v, but got %v", p, expected, got)
+		}
+	}
+
+	empty := NewRing[int](3)
+	if Sum(empty.All()) != 0 || Mean(empty.All()) != 0 || Percentile(empty.All(), 50) != 0 {
+		t.Errorf("expect zero aggregates of empty ring")
+	}
+}
@@ -1,5 +1,12 @@
 package containerx
 
+import (
+	"cmp"
+	"encoding/json"
+	"iter"
+	"slices"
+)
+
 type Empty struct{}
 
 type Set[T comparable] map[T]Empty
@@ -56,3 +63,126 @@ func (s Set[T]) Slice() []T {
 	}
 	return slice
 }
+
+// Union returns a new set of the items in either s or other.
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	result := s.Clone()
+	for item := range other {
+		result[item] = Empty{}
+	}
+	return result
+}
+
+// Intersection returns a new set of the items in both s and other.
+func (s Set[T]) Intersection(other Set[T]) Set[T] {
+	small, large := s, other
+	if small.Len() > large.Len() {
+		small, large = large, small
+	}
+
+	result := Set[T]{}
+	for item := range small {
+		if large.Has(item) {
+			result[item] = Empty{}
+		}
+	}
+	return result
+}
+
+// Difference returns a new set of the items in s but not in other.
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	result := Set[T]{}
+	for item := range s {
+		if !other.Has(item) {
+			result[item] = Empty{}
+		}
+	}
+	return result
+}
+
+// SymmetricDifference returns a new set of the items in either s or other but not both.
+func (s Set[T]) SymmetricDifference(other Set[T]) Set[T] {
+	result := s.Difference(other)
+	for item := range other {
+		if !s.Has(item) {
+			result[item] = Empty{}
+		}
+	}
+	return result
+}
+
+// IsSubset returns true if every item of s is in other.
+func (s Set[T]) IsSubset(other Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for item := range s {
+		if !other.Has(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if every item of other is in s.
+func (s Set[T]) IsSuperset(other Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+func (s Set[T]) Equal(other Set[T]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+func (s Set[T]) Clone() Set[T] {
+	result := make(Set[T], len(s))
+	for item := range s {
+		result[item] = Empty{}
+	}
+	return result
+}
+
+// Pop deletes and returns an arbitrary item.
+func (s Set[T]) Pop() (T, bool) {
+	for item := range s {
+		delete(s, item)
+		return item, true
+	}
+	var t T
+	return t, false
+}
+
+// Range calls f for every item in arbitrary order until f returns false.
+func (s Set[T]) Range(f func(T) bool) {
+	for item := range s {
+		if !f(item) {
+			return
+		}
+	}
+}
+
+// MarshalJSON encodes the set as an array.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Slice())
+}
+
+// UnmarshalJSON decodes an array into the set, it replaces the items of the set.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+	*s = NewSet(items...)
+	return nil
+}
+
+// SortedSlice returns the items of the set in ascending order.
+func SortedSlice[T cmp.Ordered](s Set[T]) []T {
+	slice := s.Slice()
+	slices.Sort(slice)
+	return slice
+}
+
+// All returns an iterator over the items in arbitrary order.
+func (s Set[T]) All() iter.Seq[T] {
+	return s.Range
+}
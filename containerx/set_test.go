@@ -1,6 +1,8 @@
 package containerx
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
 )
 
@@ -32,3 +34,88 @@ func TestSet(t *testing.T) {
 		t.Errorf("get slice error, slice: %v", slice)
 	}
 }
+
+func TestSetAlgebra(t *testing.T) {
+	a := NewSet(1, 2, 3, 4)
+	b := NewSet(3, 4, 5)
+
+	tests := []struct {
+		name     string
+		got      Set[int]
+		expected []int
+	}{
+		{"union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"intersection", a.Intersection(b), []int{3, 4}},
+		{"difference", a.Difference(b), []int{1, 2}},
+		{"symmetric difference", a.SymmetricDifference(b), []int{1, 2, 5}},
+		{"clone", a.Clone(), []int{1, 2, 3, 4}},
+	}
+	for _, test := range tests {
+		if got := SortedSlice(test.got); !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("%s: expected %v, but got %v", test.name, test.expected, got)
+		}
+	}
+
+	if a.Len() != 4 || b.Len() != 3 {
+		t.Errorf("expected operands unchanged, but got %v and %v", a, b)
+	}
+
+	sub := NewSet(3, 4)
+	if !sub.IsSubset(a) || !a.IsSuperset(sub) || a.IsSubset(sub) || b.IsSubset(a) {
+		t.Errorf("subset or superset unexpected")
+	}
+	if !a.Equal(a.Clone()) || a.Equal(b) || a.Equal(sub) {
+		t.Errorf("equal unexpected")
+	}
+}
+
+func TestSetPopAndRange(t *testing.T) {
+	s := NewSet("a", "b", "c")
+
+	visited := 0
+	s.Range(func(string) bool {
+		visited++
+		return visited < 2
+	})
+	if visited != 2 {
+		t.Errorf("expected range stopped after 2 items, but visited %d", visited)
+	}
+
+	popped := NewSet[string]()
+	for {
+		item, ok := s.Pop()
+		if !ok {
+			break
+		}
+		popped.Insert(item)
+	}
+	if s.Len() != 0 || !popped.Equal(NewSet("a", "b", "c")) {
+		t.Errorf("expected all items popped, but got %v", popped)
+	}
+}
+
+func TestSetJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Services Set[string] `json:"services"`
+	}{NewSet("b", "a")})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(data) != `{"services":["a","b"]}` && string(data) != `{"services":["b","a"]}` {
+		t.Errorf("expected set marshaled as array, but got %s", data)
+	}
+
+	var decoded struct {
+		Services Set[string] `json:"services"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if !decoded.Services.Equal(NewSet("a", "b")) {
+		t.Errorf("expected set decoded, but got %v", decoded.Services)
+	}
+
+	if err := json.Unmarshal([]byte(`{"services":{"a":{}}}`), &decoded); err == nil {
+		t.Errorf("expected error unmarshal object into set")
+	}
+}
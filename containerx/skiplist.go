@@ -0,0 +1,241 @@
+package containerx
+
+import (
+	"iter"
+	"math/rand"
+)
+
+const (
+	maxSkipListLevel = 32
+	// skipListP is the probability of a node having one more level
+	skipListP = 0.25
+)
+
+// SkipList is a map ordered by key, it finds, inserts and deletes a key,
+// finds the rank of a key and the key at a rank in O(log n) on average.
+// Every link records how many nodes it spans, like the zset of Redis, to
+// compute ranks.
+type SkipList[K, V any] struct {
+	less   func(x, y K) bool
+	head   *skipListNode[K, V]
+	level  int
+	length int
+}
+
+type skipListNode[K, V any] struct {
+	key    K
+	value  V
+	levels []skipListLevel[K, V]
+}
+
+type skipListLevel[K, V any] struct {
+	next *skipListNode[K, V]
+	// span is the number of nodes from this node to next, the nodes after
+	// this one if next is nil
+	span int
+}
+
+func NewSkipList[K, V any](less func(x, y K) bool) *SkipList[K, V] {
+	return &SkipList[K, V]{
+		less:  less,
+		head:  &skipListNode[K, V]{levels: make([]skipListLevel[K, V], maxSkipListLevel)},
+		level: 1,
+	}
+}
+
+func (s *SkipList[K, V]) Len() int {
+	return s.length
+}
+
+func (s *SkipList[K, V]) equal(x, y K) bool {
+	return !s.less(x, y) && !s.less(y, x)
+}
+
+// Set inserts the key or updates its value.
+func (s *SkipList[K, V]) Set(key K, value V) {
+	var (
+		update [maxSkipListLevel]*skipListNode[K, V]
+		rank   [maxSkipListLevel]int
+	)
+
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.levels[i].next != nil && s.less(x.levels[i].next.key, key) {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+
+	if next := x.levels[0].next; next != nil && s.equal(next.key, key) {
+		next.value = value
+		return
+	}
+
+	level := randomSkipListLevel()
+	if level > s.level {
+		for i := s.level; i < level; i++ {
+			update[i] = s.head
+			s.head.levels[i].span = s.length
+		}
+		s.level = level
+	}
+
+	node := &skipListNode[K, V]{key: key, value: value, levels: make([]skipListLevel[K, V], level)}
+	for i := 0; i < level; i++ {
+		node.levels[i].next = update[i].levels[i].next
+		update[i].levels[i].next = node
+
+		// rank[0] - rank[i] nodes are between update[i] and the new node
+		node.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+	for i := level; i < s.level; i++ {
+		update[i].levels[i].span++
+	}
+
+	s.length++
+}
+
+func (s *SkipList[K, V]) Get(key K) (V, bool) {
+	if node := s.seek(key); node != nil && s.equal(node.key, key) {
+		return node.value, true
+	}
+	var v V
+	return v, false
+}
+
+func (s *SkipList[K, V]) Delete(key K) bool {
+	var update [maxSkipListLevel]*skipListNode[K, V]
+
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && s.less(x.levels[i].next.key, key) {
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+
+	node := x.levels[0].next
+	if node == nil || !s.equal(node.key, key) {
+		return false
+	}
+
+	for i := 0; i < s.level; i++ {
+		if update[i].levels[i].next == node {
+			update[i].levels[i].span += node.levels[i].span - 1
+			update[i].levels[i].next = node.levels[i].next
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+	for s.level > 1 && s.head.levels[s.level-1].next == nil {
+		s.level--
+	}
+
+	s.length--
+	return true
+}
+
+// Rank returns the 0-based position of the key in order.
+func (s *SkipList[K, V]) Rank(key K) (int, bool) {
+	rank := 0
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && !s.less(key, x.levels[i].next.key) {
+			rank += x.levels[i].span
+			x = x.levels[i].next
+		}
+		if x != s.head && s.equal(x.key, key) {
+			return rank - 1, true
+		}
+	}
+	return 0, false
+}
+
+// At returns the key and value at the 0-based rank.
+func (s *SkipList[K, V]) At(rank int) (K, V, bool) {
+	if node := s.at(rank); node != nil {
+		return node.key, node.value, true
+	}
+	var (
+		k K
+		v V
+	)
+	return k, v, false
+}
+
+func (s *SkipList[K, V]) at(rank int) *skipListNode[K, V] {
+	if rank < 0 || rank >= s.length {
+		return nil
+	}
+
+	traversed := 0
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && traversed+x.levels[i].span <= rank+1 {
+			traversed += x.levels[i].span
+			x = x.levels[i].next
+		}
+		if traversed == rank+1 {
+			return x
+		}
+	}
+	return nil
+}
+
+// Range returns an iterator over the keys in [from, to] in order, the skip
+// list must not be modified during the iteration.
+func (s *SkipList[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for x := s.seek(from); x != nil && !s.less(to, x.key); x = x.levels[0].next {
+			if !yield(x.key, x.value) {
+				return
+			}
+		}
+	}
+}
+
+// RangeByRank returns an iterator over the keys ranked in [start, stop),
+// the skip list must not be modified during the iteration.
+func (s *SkipList[K, V]) RangeByRank(start, stop int) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if start < 0 {
+			start = 0
+		}
+		x := s.at(start)
+		for rank := start; x != nil && rank < stop; rank++ {
+			if !yield(x.key, x.value) {
+				return
+			}
+			x = x.levels[0].next
+		}
+	}
+}
+
+// All returns an iterator over the keys in order.
+func (s *SkipList[K, V]) All() iter.Seq2[K, V] {
+	return s.RangeByRank(0, s.length)
+}
+
+// seek returns the first node whose key is not less than key.
+func (s *SkipList[K, V]) seek(key K) *skipListNode[K, V] {
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && s.less(x.levels[i].next.key, key) {
+			x = x.levels[i].next
+		}
+	}
+	return x.levels[0].next
+}
+
+func randomSkipListLevel() int {
+	level := 1
+	for level < maxSkipListLevel && rand.Float64() < skipListP {
+		level++
+	}
+	return level
+}
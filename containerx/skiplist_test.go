@@ -0,0 +1,86 @@
+package containerx
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSkipList(t *testing.T) {
+	s := NewSkipList[int, string](func(x, y int) bool { return x < y })
+	model := make(map[int]string)
+
+	for i := 0; i < 5000; i++ {
+		key := rand.Intn(1000)
+		if rand.Intn(3) == 0 {
+			_, exists := model[key]
+			if s.Delete(key) != exists {
+				t.Fatalf("delete %d: expected %t", key, exists)
+			}
+			delete(model, key)
+		} else {
+			value := string(rune('a' + rand.Intn(26)))
+			s.Set(key, value)
+			model[key] = value
+		}
+	}
+
+	keys := make([]int, 0, len(model))
+	for key := range model {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	if s.Len() != len(keys) {
+		t.Fatalf("expected len %d, but got %d", len(keys), s.Len())
+	}
+
+	for rank, key := range keys {
+		if value, ok := s.Get(key); !ok || value != model[key] {
+			t.Fatalf("get %d: expected %s, but got %s", key, model[key], value)
+		}
+		if got, ok := s.Rank(key); !ok || got != rank {
+			t.Fatalf("rank of %d: expected %d, but got %d", key, rank, got)
+		}
+		if got, _, ok := s.At(rank); !ok || got != key {
+			t.Fatalf("at %d: expected %d, but got %d", rank, key, got)
+		}
+	}
+
+	if _, ok := s.Rank(-1); ok {
+		t.Errorf("expected no rank of absent key")
+	}
+	if _, _, ok := s.At(len(keys)); ok {
+		t.Errorf("expected nothing at out of range rank")
+	}
+
+	i := 0
+	for key := range s.All() {
+		if key != keys[i] {
+			t.Fatalf("all: expected %d, but got %d", keys[i], key)
+		}
+		i++
+	}
+
+	expected, got := []int{}, []int{}
+	for _, key := range keys {
+		if key >= 100 && key <= 200 {
+			expected = append(expected, key)
+		}
+	}
+	for key := range s.Range(100, 200) {
+		got = append(got, key)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("range [100, 200]: expected %v, but got %v", expected, got)
+	}
+
+	got = got[:0]
+	for key := range s.RangeByRank(10, 15) {
+		got = append(got, key)
+	}
+	if !reflect.DeepEqual(got, keys[10:15]) {
+		t.Errorf("range by rank [10, 15): expected %v, but got %v", keys[10:15], got)
+	}
+}
@@ -0,0 +1,103 @@
+package containerx
+
+import (
+	"cmp"
+	"iter"
+	"math"
+)
+
+// SortedSet is a set of members ordered by score like the zset of Redis,
+// members with the same score are ordered by the time they are added.
+type SortedSet[K comparable, S cmp.Ordered] struct {
+	list    *SkipList[scoreKey[S], K]
+	members map[K]scoreKey[S]
+	// seq orders the members with the same score
+	seq uint64
+}
+
+type scoreKey[S cmp.Ordered] struct {
+	score S
+	seq   uint64
+}
+
+func NewSortedSet[K comparable, S cmp.Ordered]() *SortedSet[K, S] {
+	return &SortedSet[K, S]{
+		list: NewSkipList[scoreKey[S], K](func(x, y scoreKey[S]) bool {
+			if x.score != y.score {
+				return x.score < y.score
+			}
+			return x.seq < y.seq
+		}),
+		members: make(map[K]scoreKey[S]),
+	}
+}
+
+func (s *SortedSet[K, S]) Len() int {
+	return len(s.members)
+}
+
+// Add adds the member or updates its score.
+func (s *SortedSet[K, S]) Add(member K, score S) {
+	if key, ok := s.members[member]; ok {
+		if key.score == score {
+			return
+		}
+		s.list.Delete(key)
+	}
+
+	s.seq++
+	key := scoreKey[S]{score: score, seq: s.seq}
+	s.list.Set(key, member)
+	s.members[member] = key
+}
+
+func (s *SortedSet[K, S]) Remove(member K) bool {
+	key, ok := s.members[member]
+	if !ok {
+		return false
+	}
+	s.list.Delete(key)
+	delete(s.members, member)
+	return true
+}
+
+func (s *SortedSet[K, S]) Score(member K) (S, bool) {
+	key, ok := s.members[member]
+	return key.score, ok
+}
+
+// Rank returns the 0-based position of the member ordered by score ascending.
+func (s *SortedSet[K, S]) Rank(member K) (int, bool) {
+	key, ok := s.members[member]
+	if !ok {
+		return 0, false
+	}
+	return s.list.Rank(key)
+}
+
+// RangeByScore returns an iterator over the members with score in [min, max]
+// ordered by score, the set must not be modified during the iteration.
+func (s *SortedSet[K, S]) RangeByScore(min, max S) iter.Seq2[K, S] {
+	return s.memberScores(s.list.Range(scoreKey[S]{score: min}, scoreKey[S]{score: max, seq: math.MaxUint64}))
+}
+
+// RangeByRank returns an iterator over the members ranked in [start, stop),
+// the set must not be modified during the iteration.
+func (s *SortedSet[K, S]) RangeByRank(start, stop int) iter.Seq2[K, S] {
+	return s.memberScores(s.list.RangeByRank(start, stop))
+}
+
+// All returns an iterator over the members ordered by score.
+func (s *SortedSet[K, S]) All() iter.Seq2[K, S] {
+	return s.memberScores(s.list.All())
+}
+
+func (s *SortedSet[K, S]) memberScores(seq iter.Seq2[scoreKey[S], K]) iter.Seq2[K, S] {
+	return func(yield func(K, S) bool) {
+		for key, member := range seq {
+			if !yield(member, key.score) {
+				return
+			}
+		}
+	}
+}
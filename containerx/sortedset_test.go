@@ -0,0 +1,51 @@
+package containerx
+
+import (
+	"iter"
+	"reflect"
+	"testing"
+)
+
+func TestSortedSet(t *testing.T) {
+	s := NewSortedSet[string, int]()
+	s.Add("alice", 30)
+	s.Add("bob", 10)
+	s.Add("carol", 20)
+	s.Add("dave", 20)
+	s.Add("bob", 40)
+
+	members := func(seq iter.Seq2[string, int]) []string {
+		var got []string
+		for member := range seq {
+			got = append(got, member)
+		}
+		return got
+	}
+
+	if got := members(s.All()); !reflect.DeepEqual(got, []string{"carol", "dave", "alice", "bob"}) {
+		t.Errorf("expected members ordered by score, but got %v", got)
+	}
+	if got := members(s.RangeByScore(20, 30)); !reflect.DeepEqual(got, []string{"carol", "dave", "alice"}) {
+		t.Errorf("expected members scored in [20, 30], but got %v", got)
+	}
+	if got := members(s.RangeByRank(1, 3)); !reflect.DeepEqual(got, []string{"dave", "alice"}) {
+		t.Errorf("expected members ranked in [1, 3), but got %v", got)
+	}
+
+	if rank, ok := s.Rank("bob"); !ok || rank != 3 {
+		t.Errorf("expected bob ranked 3, but got %d", rank)
+	}
+	if score, ok := s.Score("bob"); !ok || score != 40 {
+		t.Errorf("expected bob scored 40, but got %d", score)
+	}
+
+	if !s.Remove("carol") || s.Remove("carol") || s.Len() != 3 {
+		t.Errorf("expected carol removed")
+	}
+	if rank, ok := s.Rank("dave"); !ok || rank != 0 {
+		t.Errorf("expected dave ranked 0, but got %d", rank)
+	}
+	if _, ok := s.Rank("carol"); ok {
+		t.Errorf("expected no rank of removed member")
+	}
+}
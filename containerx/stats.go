@@ -0,0 +1,26 @@
+package containerx
+
+// CacheStats counts the lookups of a map or cache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+	// Evictions is the number of entries evicted for capacity or expired.
+	Evictions uint64
+}
+
+// HitRate returns the ratio of hits to lookups, zero if there is no lookup.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+func (s *CacheStats) record(hit bool) {
+	if hit {
+		s.Hits++
+	} else {
+		s.Misses++
+	}
+}
@@ -0,0 +1,267 @@
+package containerx
+
+import (
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// The tests run operations from many goroutines, run them with -race.
+
+const (
+	workers = 8
+	perWork = 1000
+)
+
+func parallel(f func(worker int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			f(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSyncQueue(t *testing.T) {
+	q := NewSyncQueue[int]()
+	parallel(func(worker int) {
+		for i := 0; i < perWork; i++ {
+			q.Push(worker*perWork + i)
+			q.Front()
+			q.Back()
+		}
+	})
+	if q.Len() != workers*perWork {
+		t.Fatalf("expected len %d, but got %d", workers*perWork, q.Len())
+	}
+
+	var mu sync.Mutex
+	popped := NewSet[int]()
+	parallel(func(worker int) {
+		for i := 0; i < perWork/2; i++ {
+			item, ok := q.Pop()
+			if !ok {
+				t.Errorf("expected pop succeed")
+				return
+			}
+			mu.Lock()
+			popped.Insert(item)
+			mu.Unlock()
+		}
+	})
+
+	if _, ok := q.PopIf(func(int) bool { return false }); ok {
+		t.Errorf("expected pop if failed")
+	}
+	front, _ := q.Front()
+	if item, ok := q.PopIf(func(x int) bool { return x == front }); !ok || item != front {
+		t.Errorf("expected pop if %d, but got %d, %t", front, item, ok)
+	}
+	popped.Insert(front)
+
+	rest := q.Drain()
+	popped.Insert(rest...)
+	if popped.Len() != workers*perWork || !q.Empty() {
+		t.Errorf("expected all %d items popped once, but got %d", workers*perWork, popped.Len())
+	}
+}
+
+func TestSyncSet(t *testing.T) {
+	s := NewSyncSet[int]()
+	inserted := make([]int, workers)
+	parallel(func(worker int) {
+		for i := 0; i < perWork; i++ {
+			if s.InsertIfAbsent(i) {
+				inserted[worker]++
+			}
+			s.Has(i)
+			s.HasAny(i, i+1)
+		}
+	})
+
+	total := 0
+	for _, n := range inserted {
+		total += n
+	}
+	if total != perWork || s.Len() != perWork {
+		t.Fatalf("expected %d items inserted once, but got %d inserts and len %d", perWork, total, s.Len())
+	}
+
+	popped := make([]int, workers)
+	parallel(func(worker int) {
+		for {
+			if _, ok := s.PopIf(func(x int) bool { return x%2 == 0 }); !ok {
+				return
+			}
+			popped[worker]++
+		}
+	})
+
+	total = 0
+	for _, n := range popped {
+		total += n
+	}
+	if total != perWork/2 {
+		t.Errorf("expected %d even items popped, but got %d", perWork/2, total)
+	}
+
+	rest := s.Drain()
+	if len(rest) != perWork/2 || s.Len() != 0 {
+		t.Errorf("expected %d odd items drained, but got %d", perWork/2, len(rest))
+	}
+}
+
+func TestSyncHeap(t *testing.T) {
+	h := NewSyncHeap([]int{}, func(x, y int) bool { return x < y })
+	parallel(func(worker int) {
+		for i := 0; i < perWork; i++ {
+			h.Push(worker*perWork + i)
+			h.Peek()
+		}
+	})
+
+	// pop items less than the half concurrently, every item once
+	popped := make([][]int, workers)
+	parallel(func(worker int) {
+		for {
+			item, ok := h.PopIf(func(x int) bool { return x < workers*perWork/2 })
+			if !ok {
+				return
+			}
+			popped[worker] = append(popped[worker], item)
+		}
+	})
+
+	all := []int{}
+	for _, items := range popped {
+		if !sort.IntsAreSorted(items) {
+			t.Errorf("expected items popped in order, but got %v", items)
+		}
+		all = append(all, items...)
+	}
+	if len(all) != workers*perWork/2 {
+		t.Errorf("expected %d items popped, but got %d", workers*perWork/2, len(all))
+	}
+
+	rest := h.Drain()
+	if len(rest) != workers*perWork/2 || !sort.IntsAreSorted(rest) || rest[0] != workers*perWork/2 || !h.Empty() {
+		t.Errorf("expected the rest drained in order")
+	}
+}
+
+func TestSyncRing(t *testing.T) {
+	r := NewSyncRing[int](workers * perWork)
+	parallel(func(worker int) {
+		for i := 0; i < perWork; i++ {
+			r.PushBack(worker*perWork + i)
+			r.Back()
+		}
+	})
+	if r.Len() != workers*perWork {
+		t.Fatalf("expected len %d, but got %d", workers*perWork, r.Len())
+	}
+
+	count := 0
+	r.Range(func(int) bool { count++; return true })
+	if count != workers*perWork {
+		t.Errorf("expected range %d elements, but got %d", workers*perWork, count)
+	}
+
+	var mu sync.Mutex
+	popped := NewSet[int]()
+	parallel(func(worker int) {
+		for i := 0; i < perWork/2; i++ {
+			var (
+				elem int
+				ok   bool
+			)
+			if i%2 == 0 {
+				elem, ok = r.PopFront()
+			} else {
+				elem, ok = r.PopBack()
+			}
+			if !ok {
+				t.Errorf("expected pop succeed")
+				return
+			}
+			mu.Lock()
+			popped.Insert(elem)
+			mu.Unlock()
+		}
+	})
+
+	front, _ := r.Front()
+	if elem, ok := r.PopIf(func(x int) bool { return x == front }); !ok || elem != front {
+		t.Errorf("expected pop if %d, but got %d, %t", front, elem, ok)
+	}
+	popped.Insert(front)
+	popped.Insert(r.Drain()...)
+
+	if popped.Len() != workers*perWork || r.Len() != 0 {
+		t.Errorf("expected all %d elements popped once, but got %d", workers*perWork, popped.Len())
+	}
+	if _, ok := r.PopIf(func(int) bool { return true }); ok {
+		t.Errorf("expected pop if of empty ring failed")
+	}
+}
+
+func TestSyncRingBlock(t *testing.T) {
+	r := NewSyncRing[int](2, WithRingPolicy(RingBlock))
+	r.PushBack(1, 2)
+
+	if err := r.PushBack(1, 2, 3); err != ErrRingFull {
+		t.Errorf("expected %v for elements never fit, but got %v", ErrRingFull, err)
+	}
+
+	pushed := make(chan error)
+	go func() {
+		pushed <- r.PushBack(3)
+	}()
+
+	select {
+	case <-pushed:
+		t.Fatalf("expected push blocked while the ring is full")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if elem, _ := r.PopFront(); elem != 1 {
+		t.Errorf("expected pop 1, but got %d", elem)
+	}
+	if err := <-pushed; err != nil {
+		t.Errorf("expected push succeed, but got %v", err)
+	}
+	if snapshot := r.Snapshot(); !reflect.DeepEqual(snapshot, []int{2, 3}) {
+		t.Errorf("expected ring [2 3], but got %v", snapshot)
+	}
+
+	// producers and consumers
+	var sum atomic.Int64
+	r.Clear()
+	parallel(func(worker int) {
+		if worker%2 == 0 {
+			for i := 1; i <= perWork; i++ {
+				r.PushBack(i)
+			}
+			return
+		}
+		for i := 0; i < perWork; i++ {
+			for {
+				if elem, ok := r.PopFront(); ok {
+					sum.Add(int64(elem))
+					break
+				}
+				runtime.Gosched()
+			}
+		}
+	})
+	if expected := int64(workers / 2 * perWork * (perWork + 1) / 2); sum.Load() != expected {
+		t.Errorf("expected sum %d, but got %d", expected, sum.Load())
+	}
+}
@@ -0,0 +1,80 @@
+package containerx
+
+import (
+	"iter"
+	"slices"
+	"sync"
+)
+
+// SyncHeap is a Heap safe for concurrent use. Index based methods like
+// Remove and Fix are left out, as indexes change between calls.
+type SyncHeap[T any] struct {
+	mu sync.Mutex
+	h  *Heap[T]
+}
+
+func NewSyncHeap[T any](data []T, less func(x, y T) bool) *SyncHeap[T] {
+	return &SyncHeap[T]{h: NewHeap(data, less)}
+}
+
+func (h *SyncHeap[T]) Push(x T) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.h.Push(x)
+}
+
+func (h *SyncHeap[T]) Pop() (T, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.h.Pop()
+}
+
+// PopIf pops the top item only if pred returns true for it, e.g. a task
+// is popped only if it is due.
+func (h *SyncHeap[T]) PopIf(pred func(T) bool) (T, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	item, ok := h.h.Peek()
+	if !ok || !pred(item) {
+		var t T
+		return t, false
+	}
+	return h.h.Pop()
+}
+
+// Drain pops all items in order.
+func (h *SyncHeap[T]) Drain() []T {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	items := make([]T, 0, h.h.Len())
+	for !h.h.Empty() {
+		item, _ := h.h.Pop()
+		items = append(items, item)
+	}
+	return items
+}
+
+// All returns an iterator over a snapshot of the items in heap order.
+func (h *SyncHeap[T]) All() iter.Seq[T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Values(slices.Clone(h.h.data))
+}
+
+func (h *SyncHeap[T]) Peek() (T, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.h.Peek()
+}
+
+func (h *SyncHeap[T]) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.h.Len()
+}
+
+func (h *SyncHeap[T]) Empty() bool {
+	return h.Len() == 0
+}
@@ -0,0 +1,81 @@
+package containerx
+
+import (
+	"iter"
+	"slices"
+	"sync"
+)
+
+// SyncQueue is a Queue safe for concurrent use.
+type SyncQueue[T any] struct {
+	mu sync.Mutex
+	q  *Queue[T]
+}
+
+func NewSyncQueue[T any](items ...T) *SyncQueue[T] {
+	return &SyncQueue[T]{q: New(items...)}
+}
+
+func (q *SyncQueue[T]) Push(item T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.q.Push(item)
+}
+
+func (q *SyncQueue[T]) Pop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.q.Pop()
+}
+
+// PopIf pops the front item only if pred returns true for it.
+func (q *SyncQueue[T]) PopIf(pred func(T) bool) (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	item, ok := q.q.Front()
+	if !ok || !pred(item) {
+		var t T
+		return t, false
+	}
+	return q.q.Pop()
+}
+
+// Drain pops all items, the front first.
+func (q *SyncQueue[T]) Drain() []T {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	items := q.q.data.Slice()
+	q.q.data.Clear()
+	return items
+}
+
+// All returns an iterator over a snapshot of the items from the front.
+func (q *SyncQueue[T]) All() iter.Seq[T] {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Values(q.q.data.Slice())
+}
+
+func (q *SyncQueue[T]) Front() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.q.Front()
+}
+
+func (q *SyncQueue[T]) Back() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.q.Back()
+}
+
+func (q *SyncQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.q.Len()
+}
+
+func (q *SyncQueue[T]) Empty() bool {
+	return q.Len() == 0
+}
@@ -0,0 +1,154 @@
+package containerx
+
+import (
+	"iter"
+	"slices"
+	"sync"
+)
+
+// SyncRing is a Ring safe for concurrent use, a push blocks while the ring
+// is full if the policy is RingBlock.
+type SyncRing[T any] struct {
+	mu      sync.Mutex
+	notFull *sync.Cond
+	r       *Ring[T]
+}
+
+func NewSyncRing[T any](size int, opts ...RingOption) *SyncRing[T] {
+	r := &SyncRing[T]{r: NewRing[T](size, opts...)}
+	r.notFull = sync.NewCond(&r.mu)
+	return r
+}
+
+func (r *SyncRing[T]) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Len()
+}
+
+func (r *SyncRing[T]) Cap() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Cap()
+}
+
+func (r *SyncRing[T]) PushFront(elems ...T) error {
+	return r.push(r.r.PushFront, elems)
+}
+
+func (r *SyncRing[T]) PushBack(elems ...T) error {
+	return r.push(r.r.PushBack, elems)
+}
+
+func (r *SyncRing[T]) push(push func(...T) error, elems []T) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.r.policy == RingBlock {
+		for r.r.Len()+len(elems) > r.r.Cap() {
+			if len(elems) > r.r.Cap() {
+				// never fits
+				return ErrRingFull
+			}
+			r.notFull.Wait()
+		}
+	}
+	return push(elems...)
+}
+
+func (r *SyncRing[T]) Front() (T, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Front()
+}
+
+func (r *SyncRing[T]) Back() (T, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Back()
+}
+
+func (r *SyncRing[T]) At(i int) T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.At(i)
+}
+
+func (r *SyncRing[T]) Snapshot() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Snapshot()
+}
+
+func (r *SyncRing[T]) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.r.Clear()
+	r.notFull.Broadcast()
+}
+
+func (r *SyncRing[T]) Resize(size int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.r.Resize(size)
+	r.notFull.Broadcast()
+}
+
+func (r *SyncRing[T]) PopFront() (T, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.popped(r.r.PopFront())
+}
+
+func (r *SyncRing[T]) PopBack() (T, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.popped(r.r.PopBack())
+}
+
+// popped wakes up the blocked pushes if an element is popped.
+func (r *SyncRing[T]) popped(elem T, ok bool) (T, bool) {
+	if ok {
+		r.notFull.Broadcast()
+	}
+	return elem, ok
+}
+
+// PopIf pops the front element only if pred returns true for it.
+func (r *SyncRing[T]) PopIf(pred func(T) bool) (T, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	front, ok := r.r.Front()
+	if !ok || !pred(front) {
+		var t T
+		return t, false
+	}
+	return r.popped(r.r.PopFront())
+}
+
+// Drain pops all elements, the front first.
+func (r *SyncRing[T]) Drain() []T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	elems := r.r.Snapshot()
+	r.r.Clear()
+	r.notFull.Broadcast()
+	return elems
+}
+
+// Range calls f for every element from the front under the lock until f
+// returns false, f must not call methods of the ring.
+func (r *SyncRing[T]) Range(f func(T) bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.r.Range(f)
+}
+
+// All returns an iterator over a snapshot of the elements from the front.
+func (r *SyncRing[T]) All() iter.Seq[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Values(r.r.Snapshot())
+}
@@ -0,0 +1,101 @@
+package containerx
+
+import (
+	"iter"
+	"slices"
+	"sync"
+)
+
+// SyncSet is a Set safe for concurrent use.
+type SyncSet[T comparable] struct {
+	mu sync.RWMutex
+	s  Set[T]
+}
+
+func NewSyncSet[T comparable](items ...T) *SyncSet[T] {
+	return &SyncSet[T]{s: NewSet(items...)}
+}
+
+func (s *SyncSet[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.Len()
+}
+
+func (s *SyncSet[T]) Insert(items ...T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.s.Insert(items...)
+}
+
+// InsertIfAbsent inserts the item and returns true if it is not in the set.
+func (s *SyncSet[T]) InsertIfAbsent(item T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.s.Has(item) {
+		return false
+	}
+	s.s.Insert(item)
+	return true
+}
+
+func (s *SyncSet[T]) Delete(items ...T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.s.Delete(items...)
+}
+
+// PopIf deletes and returns an arbitrary item for which pred returns true.
+func (s *SyncSet[T]) PopIf(pred func(T) bool) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for item := range s.s {
+		if pred(item) {
+			delete(s.s, item)
+			return item, true
+		}
+	}
+	var t T
+	return t, false
+}
+
+// Drain deletes and returns all items.
+func (s *SyncSet[T]) Drain() []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := s.s.Slice()
+	s.s = Set[T]{}
+	return items
+}
+
+func (s *SyncSet[T]) Has(item T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.Has(item)
+}
+
+func (s *SyncSet[T]) HasAll(items ...T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.HasAll(items...)
+}
+
+func (s *SyncSet[T]) HasAny(items ...T) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.HasAny(items...)
+}
+
+func (s *SyncSet[T]) Slice() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.s.Slice()
+}
+
+// All returns an iterator over a snapshot of the items.
+func (s *SyncSet[T]) All() iter.Seq[T] {
+	return slices.Values(s.Slice())
+}
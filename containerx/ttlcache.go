@@ -0,0 +1,140 @@
+package containerx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/qingwave/gocorex/utils/clock"
+)
+
+// TTLCache is a cache safe for concurrent use whose entries expire after a
+// time to live. Expired entries are deleted lazily when they are looked up,
+// and in the background by Run.
+type TTLCache[K comparable, V any] struct {
+	mu    sync.Mutex
+	ttl   time.Duration
+	clock clock.WithTicker
+	// entries is ordered by expiration, so expired entries are popped first
+	entries *PriorityQueue[K, ttlEntry[V]]
+	stats   CacheStats
+}
+
+type ttlEntry[V any] struct {
+	value      V
+	expiration time.Time
+}
+
+// NewTTLCache returns a cache whose entries expire after ttl by default,
+// the real clock is used if c is nil.
+func NewTTLCache[K comparable, V any](ttl time.Duration, c clock.WithTicker) *TTLCache[K, V] {
+	if ttl <= 0 {
+		panic("invaild cache ttl")
+	}
+	if c == nil {
+		c = clock.RealClock{}
+	}
+
+	return &TTLCache[K, V]{
+		ttl:   ttl,
+		clock: c,
+		entries: NewPriorityQueue[K](func(x, y ttlEntry[V]) bool {
+			return x.expiration.Before(y.expiration)
+		}),
+	}
+}
+
+// Set adds or updates the entry, which expires after the default ttl.
+func (c *TTLCache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+func (c *TTLCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries.Push(key, ttlEntry[V]{
+		value:      value,
+		expiration: c.clock.Now().Add(ttl),
+	})
+}
+
+// Get returns the value of key if it has not expired.
+func (c *TTLCache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries.Get(key)
+	if ok && !c.clock.Now().Before(entry.expiration) {
+		c.entries.Remove(key)
+		c.stats.Evictions++
+		ok = false
+	}
+
+	c.stats.record(ok)
+	if !ok {
+		var v V
+		return v, false
+	}
+	return entry.value, true
+}
+
+func (c *TTLCache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.entries.Remove(key)
+	return ok
+}
+
+// Len returns the number of entries which have not expired.
+func (c *TTLCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.deleteExpired()
+	return c.entries.Len()
+}
+
+// DeleteExpired deletes the expired entries and returns how many are deleted.
+func (c *TTLCache[K, V]) DeleteExpired() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.deleteExpired()
+}
+
+func (c *TTLCache[K, V]) deleteExpired() int {
+	now := c.clock.Now()
+	deleted := 0
+	for {
+		_, entry, ok := c.entries.PeekMin()
+		if !ok || now.Before(entry.expiration) {
+			break
+		}
+		c.entries.PopMin()
+		deleted++
+	}
+	c.stats.Evictions += uint64(deleted)
+	return deleted
+}
+
+// Run deletes the expired entries every interval until ctx is done.
+func (c *TTLCache[K, V]) Run(ctx context.Context, interval time.Duration) {
+	ticker := c.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			c.DeleteExpired()
+		}
+	}
+}
+
+func (c *TTLCache[K, V]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
@@ -0,0 +1,73 @@
+package containerx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	testingclock "github.com/qingwave/gocorex/utils/clock/testing"
+)
+
+func TestTTLCache(t *testing.T) {
+	fc := testingclock.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := NewTTLCache[string, int](time.Minute, fc)
+
+	c.Set("a", 1)
+	c.SetWithTTL("b", 2, 2*time.Minute)
+	c.SetWithTTL("c", 3, 3*time.Minute)
+
+	if value, ok := c.Get("a"); !ok || value != 1 {
+		t.Errorf("expected a:1, but got %d, %t", value, ok)
+	}
+
+	fc.Step(time.Minute)
+	// lazy expiry
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("expected a expired")
+	}
+	if c.Len() != 2 {
+		t.Errorf("expected 2 entries, but got %d", c.Len())
+	}
+
+	// setting an entry again renews it
+	c.SetWithTTL("b", 4, 2*time.Minute)
+	fc.Step(time.Minute)
+	if value, ok := c.Get("b"); !ok || value != 4 {
+		t.Errorf("expected b renewed, but got %d, %t", value, ok)
+	}
+
+	fc.Step(time.Minute)
+	if deleted := c.DeleteExpired(); deleted != 2 {
+		t.Errorf("expected 2 entries expired, but got %d", deleted)
+	}
+
+	if stats := c.Stats(); stats.Hits != 2 || stats.Misses != 1 || stats.Evictions != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestTTLCacheRun(t *testing.T) {
+	fc := testingclock.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := NewTTLCache[string, int](time.Minute, fc)
+	c.Set("a", 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		c.Run(ctx, time.Minute)
+		close(done)
+	}()
+
+	fc.BlockUntil(1)
+	fc.Step(time.Minute)
+
+	for i := 0; c.Stats().Evictions == 0; i++ {
+		if i > 500 {
+			t.Fatalf("expected the entry expired in the background")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	<-done
+}
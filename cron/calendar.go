@@ -0,0 +1,217 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+)
+
+// SkippedPolicy decides what a calendar schedule does when its wall-clock
+// time does not exist on a day, e.g. 02:30 in Europe/Berlin on the day
+// clocks spring forward from 02:00 to 03:00.
+type SkippedPolicy int
+
+const (
+	// SkippedRunAtTransition runs at the first instant after the gap,
+	// that is when the clocks jump forward (03:00 in the example above).
+	SkippedRunAtTransition SkippedPolicy = iota
+	// SkippedSkip does not run on that day.
+	SkippedSkip
+)
+
+// RepeatedPolicy decides what a calendar schedule does when its wall-clock
+// time occurs twice on a day, e.g. 02:30 in Europe/Berlin on the day
+// clocks fall back from 03:00 to 02:00.
+type RepeatedPolicy int
+
+const (
+	// RepeatedFirst runs only at the first occurrence, before the clocks fall back.
+	RepeatedFirst RepeatedPolicy = iota
+	// RepeatedLast runs only at the second occurrence, after the clocks fall back.
+	RepeatedLast
+	// RepeatedBoth runs at both occurrences.
+	RepeatedBoth
+)
+
+type CalendarOption func(*calendarSchedule)
+
+// WithSkippedPolicy sets the policy for wall-clock times skipped by a DST
+// transition, SkippedRunAtTransition by default.
+func WithSkippedPolicy(policy SkippedPolicy) CalendarOption {
+	return func(s *calendarSchedule) {
+		s.skipped = policy
+	}
+}
+
+// WithRepeatedPolicy sets the policy for wall-clock times repeated by a DST
+// transition, RepeatedFirst by default.
+func WithRepeatedPolicy(policy RepeatedPolicy) CalendarOption {
+	return func(s *calendarSchedule) {
+		s.repeated = policy
+	}
+}
+
+// calendarSchedule fires at a wall-clock time in a location, so it keeps
+// firing at the same local time across DST transitions.
+type calendarSchedule struct {
+	hour, minute int
+	location     *time.Location
+	// days describes the days the schedule fires on
+	days string
+
+	// match reports whether the schedule fires on the given date
+	match func(year int, month time.Month, day int) bool
+
+	skipped  SkippedPolicy
+	repeated RepeatedPolicy
+}
+
+// Daily runs every day at hour:minute in loc.
+func Daily(hour, minute int, loc *time.Location, opts ...CalendarOption) Schedule {
+	return newCalendarSchedule("daily", hour, minute, loc, func(int, time.Month, int) bool {
+		return true
+	}, opts...)
+}
+
+// Weekly runs every week on weekday at hour:minute in loc.
+func Weekly(weekday time.Weekday, hour, minute int, loc *time.Location, opts ...CalendarOption) Schedule {
+	if weekday < time.Sunday || weekday > time.Saturday {
+		panic(fmt.Sprintf("invaild weekday %d", weekday))
+	}
+
+	return newCalendarSchedule("weekly on "+weekday.String(), hour, minute, loc, func(year int, month time.Month, day int) bool {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() == weekday
+	}, opts...)
+}
+
+// Monthly runs every month on day at hour:minute in loc. If a month is shorter
+// than day, it runs on the last day of that month instead, so Monthly(31, ...)
+// runs at the end of every month.
+func Monthly(day, hour, minute int, loc *time.Location, opts ...CalendarOption) Schedule {
+	if day < 1 || day > 31 {
+		panic(fmt.Sprintf("invaild day of month %d", day))
+	}
+
+	return newCalendarSchedule(fmt.Sprintf("monthly on day %d", day), hour, minute, loc, func(y int, m time.Month, d int) bool {
+		return d == day || (d < day && d == daysIn(y, m))
+	}, opts...)
+}
+
+func newCalendarSchedule(days string, hour, minute int, loc *time.Location, match func(int, time.Month, int) bool, opts ...CalendarOption) Schedule {
+	if hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		panic(fmt.Sprintf("invaild wall-clock time %02d:%02d", hour, minute))
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	s := &calendarSchedule{
+		hour:     hour,
+		minute:   minute,
+		location: loc,
+		days:     days,
+		match:    match,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *calendarSchedule) aligned() bool {
+	return true
+}
+
+func (s *calendarSchedule) String() string {
+	return fmt.Sprintf("%s at %02d:%02d %s", s.days, s.hour, s.minute, s.location)
+}
+
+// maxCalendarDays bounds the search of Next, long enough for any monthly schedule.
+const maxCalendarDays = 400
+
+func (s *calendarSchedule) Next(t time.Time) time.Time {
+	local := t.In(s.location)
+	year, month, day := local.Date()
+
+	for i := 0; i < maxCalendarDays; i++ {
+		date := time.Date(year, month, day+i, 0, 0, 0, 0, time.UTC)
+		y, m, d := date.Date()
+		if !s.match(y, m, d) {
+			continue
+		}
+
+		for _, at := range s.instants(y, m, d) {
+			if at.After(t) {
+				return at.In(t.Location())
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// instants returns the instants, in ascending order, at which the schedule
+// fires on the given date after applying the DST policies.
+func (s *calendarSchedule) instants(year int, month time.Month, day int) []time.Time {
+	wall := time.Date(year, month, day, s.hour, s.minute, 0, 0, time.UTC)
+
+	// the offsets in effect a day before and after, a wall-clock time is
+	// valid under an offset if converting it back gives the same wall clock
+	before := s.offset(wall.Add(-24 * time.Hour))
+	after := s.offset(wall.Add(24 * time.Hour))
+
+	var valid []time.Time
+	for _, offset := range []int{before, after} {
+		at := time.Unix(wall.Unix()-int64(offset), 0).In(s.location)
+		if at.Hour() == s.hour && at.Minute() == s.minute && at.Day() == day {
+			if len(valid) == 0 || !valid[0].Equal(at) {
+				valid = append(valid, at)
+			}
+		}
+	}
+
+	switch len(valid) {
+	case 0:
+		if s.skipped == SkippedSkip {
+			return nil
+		}
+		return []time.Time{s.transition(wall.Unix()-int64(after), wall.Unix()-int64(before))}
+	case 2:
+		if valid[1].Before(valid[0]) {
+			valid[0], valid[1] = valid[1], valid[0]
+		}
+		switch s.repeated {
+		case RepeatedLast:
+			return valid[1:]
+		case RepeatedBoth:
+			return valid
+		default:
+			return valid[:1]
+		}
+	default:
+		return valid
+	}
+}
+
+func (s *calendarSchedule) offset(t time.Time) int {
+	_, offset := t.In(s.location).Zone()
+	return offset
+}
+
+// transition finds the first second in (lo, hi] whose offset differs from lo's.
+func (s *calendarSchedule) transition(lo, hi int64) time.Time {
+	offset := s.offset(time.Unix(lo, 0))
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		if s.offset(time.Unix(mid, 0)) == offset {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return time.Unix(hi, 0).In(s.location)
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
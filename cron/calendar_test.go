@@ -0,0 +1,81 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendarSchedule(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("load location failed: %v", err)
+	}
+
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2023, month, day, hour, minute, 0, 0, berlin)
+	}
+	utc := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2023, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		from     time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "daily",
+			schedule: Daily(2, 30, berlin),
+			from:     at(3, 24, 12, 0),
+			expected: []time.Time{at(3, 25, 2, 30), at(3, 26, 3, 0), at(3, 27, 2, 30)},
+		},
+		{
+			name:     "daily skip gap",
+			schedule: Daily(2, 30, berlin, WithSkippedPolicy(SkippedSkip)),
+			from:     at(3, 24, 12, 0),
+			expected: []time.Time{at(3, 25, 2, 30), at(3, 27, 2, 30)},
+		},
+		{
+			name:     "daily repeated first",
+			schedule: Daily(2, 30, berlin),
+			from:     at(10, 28, 12, 0),
+			expected: []time.Time{utc(10, 29, 0, 30), at(10, 30, 2, 30)},
+		},
+		{
+			name:     "daily repeated last",
+			schedule: Daily(2, 30, berlin, WithRepeatedPolicy(RepeatedLast)),
+			from:     at(10, 28, 12, 0),
+			expected: []time.Time{utc(10, 29, 1, 30), at(10, 30, 2, 30)},
+		},
+		{
+			name:     "daily repeated both",
+			schedule: Daily(2, 30, berlin, WithRepeatedPolicy(RepeatedBoth)),
+			from:     at(10, 28, 12, 0),
+			expected: []time.Time{utc(10, 29, 0, 30), utc(10, 29, 1, 30), at(10, 30, 2, 30)},
+		},
+		{
+			name:     "weekly",
+			schedule: Weekly(time.Monday, 9, 0, berlin),
+			from:     at(7, 10, 9, 0),
+			expected: []time.Time{at(7, 17, 9, 0), at(7, 24, 9, 0)},
+		},
+		{
+			name:     "monthly clamp to last day",
+			schedule: Monthly(31, 0, 0, berlin),
+			from:     at(1, 31, 0, 0),
+			expected: []time.Time{at(2, 28, 0, 0), at(3, 31, 0, 0), at(4, 30, 0, 0)},
+		},
+	}
+
+	for _, test := range tests {
+		next := test.from
+		for _, expected := range test.expected {
+			next = test.schedule.Next(next)
+			if !next.Equal(expected) {
+				t.Errorf("%s: expected %v, but got %v", test.name, expected, next)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var ErrTaskNotFound = errors.New("task not found")
+
+type TaskState string
+
+const (
+	TaskScheduled TaskState = "Scheduled"
+	TaskRunning   TaskState = "Running"
+	TaskPaused    TaskState = "Paused"
+)
+
+// TaskInfo is a snapshot of a task returned by List.
+type TaskInfo struct {
+	Name string
+	// Schedule describes the schedule, see describe.
+	Schedule string
+	// Next is the next time the task is due, zero if it is due right now.
+	Next  time.Time
+	State TaskState
+	// Running is the number of in-flight runs.
+	Running int
+}
+
+func (t *Task) initState() {
+	if t.state == nil {
+		t.state = &taskState{}
+	}
+}
+
+func (t *Task) info() TaskInfo {
+	t.state.mu.Lock()
+	defer t.state.mu.Unlock()
+
+	info := TaskInfo{
+		Name:     t.Name(),
+		Schedule: describe(t.Schedule),
+		Next:     t.next,
+		State:    TaskScheduled,
+		Running:  t.state.running,
+	}
+	if t.state.paused {
+		info.State = TaskPaused
+	} else if t.state.running > 0 {
+		info.State = TaskRunning
+	}
+	return info
+}
+
+func (t *Task) paused() bool {
+	t.state.mu.Lock()
+	defer t.state.mu.Unlock()
+	return t.state.paused
+}
+
+func (t *Task) setPaused(paused bool) {
+	t.state.mu.Lock()
+	defer t.state.mu.Unlock()
+	t.state.paused = paused
+}
+
+// describe returns the description of a schedule, which is the expression
+// for a parsed one, or its type if it does not implement fmt.Stringer.
+func describe(s Schedule) string {
+	if stringer, ok := s.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+	return fmt.Sprintf("%T", s)
+}
+
+func sortTaskInfos(infos []TaskInfo) {
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Name < infos[j].Name
+	})
+}
@@ -0,0 +1,173 @@
+package cron
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestCronControl(t *testing.T) {
+	job, ch, fc := newTestJob("hourly")
+	start := fc.Now()
+
+	c := NewCron(logr.Discard(), WithClock(fc))
+	c.Add(&Task{Job: job, Schedule: Every(time.Hour)})
+
+	go c.Run()
+	defer c.Stop()
+
+	if err := c.Pause("unknown"); err != ErrTaskNotFound {
+		t.Errorf("expected error %v, but got %v", ErrTaskNotFound, err)
+	}
+
+	if err := c.Pause("hourly"); err != nil {
+		t.Fatalf("pause failed: %v", err)
+	}
+	infos := c.List()
+	if len(infos) != 1 || infos[0].State != TaskPaused || !infos[0].Next.Equal(start.Add(time.Hour)) {
+		t.Errorf("unexpected task info: %+v", infos)
+	}
+
+	fc.BlockUntil(1)
+	fc.Step(time.Hour)
+	expectNoRun(t, ch)
+
+	c.Resume("hourly")
+	if err := c.TriggerNow("hourly"); err != nil {
+		t.Fatalf("trigger failed: %v", err)
+	}
+	expectRun(t, ch, start.Add(time.Hour))
+
+	c.Reschedule("hourly", Every(10*time.Minute))
+	fc.BlockUntil(1)
+	fc.Step(10 * time.Minute)
+	expectRun(t, ch, start.Add(70*time.Minute))
+
+	if c.Len() != 1 {
+		t.Errorf("expected %d tasks, but got %d", 1, c.Len())
+	}
+}
+
+func TestTimeWheelControl(t *testing.T) {
+	job, ch, fc := newTestJob("every-2s")
+	start := fc.Now()
+
+	tw := NewTimeWheel(time.Second, 5, logr.Discard(), WithClock(fc)).(*TimeWheel)
+	tw.Add(&Task{Job: job, Schedule: Every(2 * time.Second)})
+
+	tick := func() {
+		fc.Step(time.Second)
+		tw.tick(fc.Now())
+	}
+
+	tw.Pause("every-2s")
+	tick()
+	tick()
+	expectNoRun(t, ch)
+
+	tw.Resume("every-2s")
+	tw.TriggerNow("every-2s")
+	expectRun(t, ch, start.Add(2*time.Second))
+
+	tw.Reschedule("every-2s", Every(3*time.Second))
+	if infos := tw.List(); len(infos) != 1 || !infos[0].Next.Equal(start.Add(5*time.Second)) {
+		t.Errorf("unexpected task info: %+v", infos)
+	}
+	tick()
+	tick()
+	expectNoRun(t, ch)
+	tick()
+	expectRun(t, ch, start.Add(5*time.Second))
+
+	tw.Remove("every-2s")
+	for i := 0; i < 6; i++ {
+		tick()
+	}
+	expectNoRun(t, ch)
+}
+
+func TestControlConcurrently(t *testing.T) {
+	for _, c := range []Interface{
+		NewCron(logr.Discard()),
+		NewTimeWheel(time.Millisecond, 10, logr.Discard()),
+	} {
+		go c.Run()
+
+		// controls keep going until the scheduler is stopped, and once more
+		// after it, so they overlap with Stop
+		var begun, wg sync.WaitGroup
+		stopped := make(chan struct{})
+		for i := 0; i < 4; i++ {
+			begun.Add(1)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				begun.Done()
+				for {
+					c.Add(&Task{Job: SimpleJob("job", func() error { return nil }), Schedule: Every(time.Millisecond)})
+					c.Pause("job")
+					c.Resume("job")
+					c.TriggerNow("job")
+					c.Reschedule("job", Every(2*time.Millisecond))
+					c.List()
+					c.Remove("job")
+
+					select {
+					case <-stopped:
+						return
+					default:
+					}
+				}
+			}()
+		}
+
+		begun.Wait()
+		c.Stop()
+		close(stopped)
+		wg.Wait()
+	}
+}
+
+func TestHooksCallScheduler(t *testing.T) {
+	for _, newScheduler := range []func(...Option) Interface{
+		func(opts ...Option) Interface { return NewCron(logr.Discard(), opts...) },
+		func(opts ...Option) Interface { return NewTimeWheel(time.Second, 10, logr.Discard(), opts...) },
+	} {
+		var c Interface
+		skipped := make(chan struct{}, 1)
+		c = newScheduler(WithHooks(Hooks{
+			OnSkip: func(e Execution) {
+				c.List()
+				c.Pause(e.Name)
+				skipped <- struct{}{}
+			},
+		}))
+
+		job := newBlockingJob()
+		c.Add(&Task{Job: job, Schedule: Every(time.Hour), Concurrency: ForbidConcurrent})
+
+		c.TriggerNow(job.Name())
+		waitStarted(t, job, 1)
+
+		done := make(chan struct{})
+		go func() {
+			c.TriggerNow(job.Name())
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected hook calling the scheduler not deadlock")
+		}
+
+		<-skipped
+		if infos := c.List(); len(infos) != 1 || infos[0].State != TaskPaused {
+			t.Errorf("expected task paused by the hook, but got %+v", infos)
+		}
+
+		close(job.release)
+		c.Stop()
+	}
+}
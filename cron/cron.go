@@ -1,7 +1,9 @@
 package cron
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -16,6 +18,16 @@ type Interface interface {
 	Add(*Task)
 	Remove(string)
 	Len() int
+	History(string) []Execution
+
+	// Pause stops running the task when it is due until it is resumed.
+	Pause(string) error
+	Resume(string) error
+	// TriggerNow runs the task right now, regardless of its schedule.
+	TriggerNow(string) error
+	// Reschedule replaces the schedule of the task.
+	Reschedule(string, Schedule) error
+	List() []TaskInfo
 }
 
 type Job interface {
@@ -23,6 +35,39 @@ type Job interface {
 	Exec() error
 }
 
+// ContextJob is a Job that observes cancellation through the context of the
+// run, which is done when the run times out, is replaced according to
+// ReplaceConcurrent or the scheduler is stopped.
+type ContextJob interface {
+	Job
+	ExecContext(ctx context.Context) error
+}
+
+// SimpleContextJob returns a ContextJob, Exec runs it with a background context.
+func SimpleContextJob(name string, exec func(ctx context.Context) error) ContextJob {
+	return &contextJob{
+		name: name,
+		exec: exec,
+	}
+}
+
+type contextJob struct {
+	name string
+	exec func(ctx context.Context) error
+}
+
+func (j *contextJob) Name() string {
+	return j.name
+}
+
+func (j *contextJob) Exec() error {
+	return j.exec(context.Background())
+}
+
+func (j *contextJob) ExecContext(ctx context.Context) error {
+	return j.exec(ctx)
+}
+
 func SimpleJob(name string, exec func() error) Job {
 	return &job{
 		name: name,
@@ -56,7 +101,7 @@ type schedule struct {
 }
 
 func (s *schedule) nextTime(t time.Time) time.Time {
-	if s.from.IsZero() {
+	if s.from.IsZero() && s.interval == 0 {
 		return time.Time{}
 	}
 
@@ -86,9 +131,46 @@ func (s *schedule) Next(t time.Time) time.Time {
 	return next
 }
 
+// skip skips the runs in (prev, now] but the latest n at once, keeping the
+// phase and the number of runs left, and returns the time of the last run
+// skipped, or prev if none is.
+func (s *schedule) skip(prev, now time.Time, n int) time.Time {
+	if s.interval <= 0 || s.from.After(prev) || !now.After(prev) {
+		return prev
+	}
+
+	missed := int64(now.Sub(prev) / s.interval)
+	if s.times >= 0 && missed > int64(s.times) {
+		missed = int64(s.times)
+	}
+
+	skipped := missed - int64(n)
+	if skipped <= 0 {
+		return prev
+	}
+
+	if s.times > 0 {
+		s.times -= int(skipped)
+	}
+	return prev.Add(time.Duration(skipped) * s.interval)
+}
+
+func (s *schedule) String() string {
+	switch {
+	case s.interval == 0:
+		return fmt.Sprintf("once at %s", s.from.Format(time.RFC3339))
+	case s.from.IsZero():
+		return fmt.Sprintf("every %v", s.interval)
+	case s.times < 0:
+		return fmt.Sprintf("every %v from %s", s.interval, s.from.Format(time.RFC3339))
+	default:
+		return fmt.Sprintf("every %v from %s, %d times left", s.interval, s.from.Format(time.RFC3339), s.times)
+	}
+}
+
+// Every runs every duration, starting one duration after the task is added.
 func Every(duration time.Duration) Schedule {
 	return &schedule{
-		from:     time.Now(),
 		times:    RunAlways,
 		interval: duration,
 	}
@@ -96,7 +178,7 @@ func Every(duration time.Duration) Schedule {
 
 func EveryAt(from time.Time, duration time.Duration) Schedule {
 	return &schedule{
-		from:     time.Now(),
+		from:     from,
 		times:    RunAlways,
 		interval: duration,
 	}
@@ -121,50 +203,158 @@ type Task struct {
 	next time.Time
 	Job
 	Schedule
+
+	// Concurrency decides whether runs of the task may overlap, AllowConcurrent by default.
+	Concurrency ConcurrencyPolicy
+	// Timeout is the deadline of each run, no timeout if it is zero.
+	Timeout time.Duration
+	// Retry retries a failed run, no retry if it is nil.
+	Retry *RetryPolicy
+
+	state *taskState
 }
 
 type Cron struct {
-	tasks   *containerx.Heap[*Task]
-	set     containerx.Set[string]
+	mu      sync.Mutex
+	tasks   *containerx.PriorityQueue[string, *Task]
 	new     chan struct{}
 	started *atomic.Bool
-	logger  logr.Logger
+	stopCh  chan struct{}
+	stopped sync.Once
+
+	*runner
 }
 
-func NewCron(logger logr.Logger) Interface {
-	h := containerx.NewHeap([]*Task{}, func(x, y *Task) bool {
+func NewCron(logger logr.Logger, opts ...Option) Interface {
+	option := newOptions(opts...)
+
+	pq := containerx.NewPriorityQueue[string](func(x, y *Task) bool {
 		return x.next.Before(y.next)
 	})
 
 	return &Cron{
-		tasks:   h,
-		set:     containerx.NewSet[string](),
+		tasks:   pq,
 		new:     make(chan struct{}, 8),
 		started: new(atomic.Bool),
-		logger:  logger,
+		stopCh:  make(chan struct{}),
+		runner:  newRunner(logger, option),
 	}
 }
 
+// Add adds the task, it replaces the task with the same name.
 func (c *Cron) Add(task *Task) {
+	defer c.callSkipped()
+	task.initState()
+
+	now := c.clock.Now()
+	if !c.restore(task, now) {
+		return
+	}
+
 	if task.next.IsZero() {
-		task.next = task.Schedule.Next(time.Now())
+		task.next = task.Schedule.Next(now)
+	}
+
+	c.mu.Lock()
+	c.push(task)
+	c.mu.Unlock()
+
+	c.notify()
+}
+
+// push pushes the task into the queue, it replaces the task with the same name.
+func (c *Cron) push(task *Task) {
+	c.tasks.Push(task.Name(), task)
+}
+
+// notify wakes up Run to recalculate the next task.
+func (c *Cron) notify() {
+	if !c.started.Load() {
+		return
 	}
-	c.tasks.Push(task)
-	c.set.Insert(task.Name())
 
-	if c.started.Load() {
-		c.new <- struct{}{}
+	select {
+	case c.new <- struct{}{}:
+	default:
 	}
 }
 
 func (c *Cron) Remove(name string) {
-	c.set.Delete(name)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tasks.Remove(name)
 }
 
 func (c *Cron) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	return c.tasks.Len()
 }
 
+func (c *Cron) Pause(name string) error {
+	return c.with(name, func(task *Task) {
+		task.setPaused(true)
+	})
+}
+
+func (c *Cron) Resume(name string) error {
+	return c.with(name, func(task *Task) {
+		task.setPaused(false)
+	})
+}
+
+// TriggerNow runs the task right now according to its concurrency policy,
+// it does not change the next scheduled run.
+func (c *Cron) TriggerNow(name string) error {
+	defer c.callSkipped()
+	return c.with(name, func(task *Task) {
+		c.runScheduled(task, []time.Time{c.clock.Now()})
+	})
+}
+
+func (c *Cron) Reschedule(name string, schedule Schedule) error {
+	err := c.with(name, func(task *Task) {
+		// replace with a copy, the old one may still be read by its runs
+		rescheduled := *task
+		rescheduled.Schedule = schedule
+		rescheduled.next = schedule.Next(c.clock.Now())
+		if rescheduled.next.IsZero() {
+			c.tasks.Remove(name)
+			return
+		}
+		c.push(&rescheduled)
+	})
+	if err == nil {
+		c.notify()
+	}
+	return err
+}
+
+func (c *Cron) List() []TaskInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	infos := make([]TaskInfo, 0, c.tasks.Len())
+	c.tasks.Range(func(_ string, task *Task) bool {
+		infos = append(infos, task.info())
+		return true
+	})
+	sortTaskInfos(infos)
+	return infos
+}
+
+func (c *Cron) with(name string, f func(*Task)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	task, ok := c.tasks.Get(name)
+	if !ok {
+		return ErrTaskNotFound
+	}
+	f(task)
+	return nil
+}
+
 func (c *Cron) Run() error {
 	c.started.Store(true)
 
@@ -182,54 +372,61 @@ func (c *Cron) Run() error {
 const infTime time.Duration = 1<<63 - 1
 
 func (c *Cron) runTask() {
-	now := time.Now()
+	now := c.clock.Now()
 	duration := infTime
-	task, ok := c.tasks.Peek()
-	if ok {
-		if !c.set.Has(task.Name()) {
-			c.tasks.Pop()
-			return
-		}
 
+	c.mu.Lock()
+	_, task, ok := c.tasks.PeekMin()
+	if ok {
 		if task.next.After(now) {
 			duration = task.next.Sub(now)
 		} else {
 			duration = 0
 		}
 	}
+	c.mu.Unlock()
 
-	timer := time.NewTimer(duration)
+	timer := c.clock.NewTimer(duration)
 	defer timer.Stop()
 
 	select {
+	case <-c.stopCh:
+		// stopped, maybe before Run is called
+		c.started.Store(false)
+		return
 	case <-c.new:
 		return
-	case <-timer.C:
+	case <-timer.C():
 	}
 
-	task, ok = c.tasks.Pop()
-	if !ok {
+	defer c.callSkipped()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now = c.clock.Now()
+	_, task, ok = c.tasks.PeekMin()
+	if !ok || task.next.After(now) {
 		return
 	}
 
-	go func() {
-		start := time.Now()
-		if err := task.Exec(); err != nil {
-			c.logger.Info(fmt.Sprintf("Run job [%s] failed: %v", task.Name(), err))
-			return
-		}
-		c.logger.Info(fmt.Sprintf("Run job [%s] successfully, duration %v", task.Name(), time.Since(start)))
-	}()
+	if !task.paused() {
+		c.run(task)
+	}
 
-	task.next = task.Next(time.Now())
+	task.next = task.Next(now)
 	if task.next.IsZero() {
-		c.set.Delete(task.Name())
+		c.tasks.Remove(task.Name())
 	} else {
-		c.tasks.Push(task)
+		c.tasks.Update(task.Name(), task)
 	}
 }
 
+// Stop stops scheduling and cancels the running jobs, see WithStopTimeout.
+// It is safe to call Stop more than once.
 func (c *Cron) Stop() {
-	c.started.Store(false)
-	close(c.new)
+	c.stopped.Do(func() {
+		c.started.Store(false)
+		close(c.stopCh)
+		c.stop()
+	})
 }
@@ -0,0 +1,83 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	testingclock "github.com/qingwave/gocorex/utils/clock/testing"
+)
+
+func newTestJob(name string) (Job, chan time.Time, *testingclock.FakeClock) {
+	fc := testingclock.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	ch := make(chan time.Time, 16)
+	return SimpleJob(name, func() error {
+		ch <- fc.Now()
+		return nil
+	}), ch, fc
+}
+
+func expectRun(t *testing.T, ch chan time.Time, at time.Time) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		if !got.Equal(at) {
+			t.Errorf("expected job run at %v, but got %v", at, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected job run at %v, but not run", at)
+	}
+}
+
+func expectNoRun(t *testing.T, ch chan time.Time) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		t.Errorf("expected job not run, but run at %v", got)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestCronWithClock(t *testing.T) {
+	job, ch, fc := newTestJob("hourly")
+	start := fc.Now()
+
+	c := NewCron(logr.Discard(), WithClock(fc))
+	c.Add(&Task{Job: job, Schedule: Every(time.Hour)})
+
+	go c.Run()
+	defer c.Stop()
+
+	for i := 1; i <= 3; i++ {
+		fc.BlockUntil(1)
+		fc.Step(30 * time.Minute)
+		expectNoRun(t, ch)
+
+		fc.BlockUntil(1)
+		fc.Step(30 * time.Minute)
+		expectRun(t, ch, start.Add(time.Duration(i)*time.Hour))
+	}
+}
+
+func TestTimeWheelWithClock(t *testing.T) {
+	job, ch, fc := newTestJob("every-3s")
+	start := fc.Now()
+
+	tw := NewTimeWheel(time.Second, 5, logr.Discard(), WithClock(fc)).(*TimeWheel)
+	tw.Add(&Task{Job: job, Schedule: Every(3 * time.Second)})
+	tw.Add(&Task{Job: SimpleJob("once", func() error { return nil }), Schedule: Once(start.Add(-time.Second))})
+
+	for i := 1; i <= 9; i++ {
+		fc.Step(time.Second)
+		tw.tick(fc.Now())
+		if i%3 == 0 {
+			expectRun(t, ch, start.Add(time.Duration(i)*time.Second))
+		} else {
+			expectNoRun(t, ch)
+		}
+	}
+
+	if tw.Len() != 1 {
+		t.Errorf("expected %d tasks, but got %d", 1, tw.Len())
+	}
+}
@@ -0,0 +1,111 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qingwave/gocorex/syncx"
+)
+
+// DistributedLock guards every run with a lock, so when the same tasks are
+// scheduled by several replicas only one of them runs each occurrence.
+type DistributedLock struct {
+	// NewLocker returns a locker for key, the key is unique per job and
+	// scheduled time, e.g. a redislock, etcdlock or zklock on that key.
+	NewLocker func(key string) (syncx.Locker, error)
+	// MinHold is the minimum time the lock is held since the run starts, so
+	// a replica whose tick is late does not run the occurrence again. It
+	// should be longer than the clock skew between replicas plus the max
+	// Jitter or HashOffset of the schedules, and the expiration of the locker
+	// should be longer than MinHold.
+	MinHold time.Duration
+}
+
+// WithDistributedLock takes the lock per job and scheduled time before every
+// run, the run is skipped if the lock is held by another replica.
+//
+// Replicas only agree on an occurrence if they compute the same scheduled
+// time, which holds for wall-clock aligned schedules, i.e. parsed cron
+// expressions and calendar schedules, also when they are shifted by Jitter or
+// a per-host HashOffset, as the undelayed time is locked. Every, EveryAt, At and @every count
+// from the time each replica adds the task or runs it, so their occurrences
+// differ between replicas and are not deduplicated, use RunWhileLeading for
+// them instead.
+func WithDistributedLock(lock DistributedLock) Option {
+	return func(opt *CronOption) {
+		if lock.NewLocker != nil {
+			opt.lock = &lock
+		}
+	}
+}
+
+// lockKey is unique per job and occurrence, the time of a jittered or
+// offset occurrence is the undelayed one, which is the same on all replicas.
+func lockKey(s Schedule, e Execution) string {
+	return fmt.Sprintf("%s/%d", e.Name, unshift(s, e.Scheduled).Unix())
+}
+
+// lock tries to lock the occurrence of e, it returns a release func if the
+// lock is acquired by this replica.
+func (r *runner) lock(ctx context.Context, task *Task, e Execution) (func(), bool) {
+	key := lockKey(task.Schedule, e)
+	locker, err := r.locker.NewLocker(key)
+	if err != nil {
+		r.logger.Info(fmt.Sprintf("Failed to create locker for job [%s]: %v", e.Name, err))
+		return nil, false
+	}
+
+	ok, err := locker.TryLock(ctx)
+	if err != nil || !ok {
+		if err != nil {
+			r.logger.Info(fmt.Sprintf("Failed to lock job [%s]: %v", e.Name, err))
+		} else {
+			r.logger.Info(fmt.Sprintf("Skip job [%s], %s is locked by another replica", e.Name, key))
+		}
+		locker.Close()
+		return nil, false
+	}
+
+	start := r.clock.Now()
+	release := func() {
+		if err := locker.UnLock(context.Background()); err != nil {
+			r.logger.Info(fmt.Sprintf("Failed to unlock job [%s]: %v", e.Name, err))
+		}
+		locker.Close()
+	}
+
+	return func() {
+		hold := r.locker.MinHold - r.clock.Since(start)
+		if hold <= 0 {
+			release()
+			return
+		}
+
+		go func() {
+			timer := r.clock.NewTimer(hold)
+			defer timer.Stop()
+
+			select {
+			case <-timer.C():
+			case <-r.ctx.Done():
+			}
+			release()
+		}()
+	}, true
+}
+
+// RunWhileLeading returns a func that runs c until ctx is done, it is meant
+// to be the OnStartedLeading callback of leaderelection, so the scheduler
+// only runs on the leader and stops once the leadership is lost. As c cannot be restarted after it is stopped, a
+// new scheduler should be created for every leader term.
+func RunWhileLeading(c Interface) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		go func() {
+			<-ctx.Done()
+			c.Stop()
+		}()
+
+		c.Run()
+	}
+}
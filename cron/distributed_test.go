@@ -0,0 +1,183 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/qingwave/gocorex/syncx"
+	testingclock "github.com/qingwave/gocorex/utils/clock/testing"
+)
+
+// memLocks is an in-memory lock service shared by replicas.
+type memLocks struct {
+	mu    sync.Mutex
+	locks map[string]bool
+}
+
+type memLocker struct {
+	locks *memLocks
+	key   string
+}
+
+func (l *memLocker) Lock(ctx context.Context) error {
+	return nil
+}
+
+func (l *memLocker) TryLock(ctx context.Context) (bool, error) {
+	l.locks.mu.Lock()
+	defer l.locks.mu.Unlock()
+	if l.locks.locks[l.key] {
+		return false, nil
+	}
+	l.locks.locks[l.key] = true
+	return true, nil
+}
+
+func (l *memLocker) UnLock(ctx context.Context) error {
+	l.locks.mu.Lock()
+	defer l.locks.mu.Unlock()
+	delete(l.locks.locks, l.key)
+	return nil
+}
+
+func (l *memLocker) Close() error {
+	return nil
+}
+
+func (m *memLocks) held(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.locks[key]
+}
+
+func TestDistributedLock(t *testing.T) {
+	fc := testingclock.NewFakeClock(time.Now())
+	locks := &memLocks{locks: make(map[string]bool)}
+	lock := DistributedLock{
+		NewLocker: func(key string) (syncx.Locker, error) {
+			return &memLocker{locks: locks, key: key}, nil
+		},
+		MinHold: time.Minute,
+	}
+
+	var runs atomic.Int32
+	job := SimpleJob("singleton", func() error {
+		runs.Add(1)
+		return nil
+	})
+
+	scheduled := fc.Now()
+	replicas := make([]*runner, 3)
+	for i := range replicas {
+		replicas[i] = newRunner(logr.Discard(), newOptions(WithClock(fc), WithDistributedLock(lock)))
+		replicas[i].run(&Task{Job: job, next: scheduled})
+	}
+	for _, r := range replicas {
+		r.inflight.Wait()
+	}
+
+	if runs.Load() != 1 {
+		t.Errorf("expected run once, but got %d", runs.Load())
+	}
+
+	// a late replica can not run the same occurrence while the lock is held
+	key := lockKey(nil, Execution{Name: job.Name(), Scheduled: scheduled})
+	fc.BlockUntil(1)
+	if !locks.held(key) {
+		t.Errorf("expected lock held for %v", lock.MinHold)
+	}
+
+	fc.Step(time.Minute)
+	for i := 0; i < 100 && locks.held(key); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if locks.held(key) {
+		t.Errorf("expected lock released after %v", lock.MinHold)
+	}
+
+	// next occurrence
+	replicas[1].run(&Task{Job: job, next: scheduled.Add(time.Hour)})
+	replicas[1].inflight.Wait()
+	if runs.Load() != 2 {
+		t.Errorf("expected run twice, but got %d", runs.Load())
+	}
+}
+
+func TestDistributedLockAlignedSchedules(t *testing.T) {
+	fc := testingclock.NewFakeClock(time.Date(2023, 7, 9, 14, 45, 0, 0, time.UTC))
+	locks := &memLocks{locks: make(map[string]bool)}
+	lock := DistributedLock{
+		NewLocker: func(key string) (syncx.Locker, error) {
+			return &memLocker{locks: locks, key: key}, nil
+		},
+		MinHold: time.Hour,
+	}
+
+	hosts := 0
+	tests := []struct {
+		name     string
+		schedule func() Schedule
+		runs     int32
+	}{
+		{name: "cron expression", schedule: func() Schedule { return MustParse("* * * * *") }, runs: 1},
+		{name: "jitter", schedule: func() Schedule { return Jitter(MustParse("* * * * *"), 30*time.Second) }, runs: 1},
+		{name: "hash offset", schedule: func() Schedule {
+			hosts++
+			return HashOffset(MustParse("* * * * *"), fmt.Sprintf("host-%d", hosts), 30*time.Second)
+		}, runs: 1},
+		{name: "jittered hash offset", schedule: func() Schedule {
+			hosts++
+			return Jitter(HashOffset(MustParse("* * * * *"), fmt.Sprintf("host-%d", hosts), 20*time.Second), 20*time.Second)
+		}, runs: 1},
+		// replicas adding the task at different times get different occurrences
+		{name: "every", schedule: func() Schedule { return Every(time.Minute) }, runs: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var runs atomic.Int32
+			job := SimpleJob(test.name, func() error {
+				runs.Add(1)
+				return nil
+			})
+
+			// replicas add the task in the same minute, before the shifted runs
+			for _, added := range []time.Duration{-30 * time.Second, -20 * time.Second} {
+				r := newRunner(logr.Discard(), newOptions(WithClock(fc), WithDistributedLock(lock)))
+				s := test.schedule()
+				r.run(&Task{Job: job, Schedule: s, next: s.Next(fc.Now().Add(added))})
+				r.inflight.Wait()
+			}
+
+			if runs.Load() != test.runs {
+				t.Errorf("expected %d runs, but got %d", test.runs, runs.Load())
+			}
+		})
+	}
+}
+
+func TestRunWhileLeading(t *testing.T) {
+	c := NewCron(logr.Discard())
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		RunWhileLeading(c)(ctx)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected cron stopped when leadership lost")
+	}
+
+	// the owner still stops it on shutdown
+	c.Stop()
+}
@@ -0,0 +1,125 @@
+package cron
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// TaskStatus is a task exposed by the admin handler.
+type TaskStatus struct {
+	Name     string    `json:"name"`
+	Schedule string    `json:"schedule"`
+	Next     time.Time `json:"next"`
+	State    TaskState `json:"state"`
+	Running  int       `json:"running"`
+	// LastRun is the latest finished run, nil if the task has not run yet.
+	LastRun *RunResult `json:"lastRun,omitempty"`
+}
+
+// RunResult is the result of a finished run.
+type RunResult struct {
+	Scheduled time.Time `json:"scheduled"`
+	Start     time.Time `json:"start"`
+	Duration  string    `json:"duration"`
+	Attempt   int       `json:"attempt"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Handler is an admin http.Handler of a cron scheduler.
+//
+// GET lists the tasks as JSON. POST runs an action on a task, with the form
+// values name and action, which is one of trigger, pause, resume or remove,
+// e.g. `curl -X POST -d name=backup -d action=pause`.
+type Handler struct {
+	cron Interface
+}
+
+func NewHandler(c Interface) *Handler {
+	return &Handler{cron: c}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.writeJSON(w, h.tasks())
+	case http.MethodPost:
+		h.action(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) tasks() []TaskStatus {
+	infos := h.cron.List()
+	tasks := make([]TaskStatus, 0, len(infos))
+	for _, info := range infos {
+		task := TaskStatus{
+			Name:     info.Name,
+			Schedule: info.Schedule,
+			Next:     info.Next,
+			State:    info.State,
+			Running:  info.Running,
+		}
+		if history := h.cron.History(info.Name); len(history) > 0 {
+			last := history[len(history)-1]
+			task.LastRun = &RunResult{
+				Scheduled: last.Scheduled,
+				Start:     last.Start,
+				Duration:  last.Duration.String(),
+				Attempt:   last.Attempt,
+			}
+			if last.Err != nil {
+				task.LastRun.Error = last.Err.Error()
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+func (h *Handler) action(w http.ResponseWriter, r *http.Request) {
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "name must not be empty", http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	switch action := r.FormValue("action"); action {
+	case "trigger":
+		err = h.cron.TriggerNow(name)
+	case "pause":
+		err = h.cron.Pause(name)
+	case "resume":
+		err = h.cron.Resume(name)
+	case "remove":
+		// Remove ignores unknown tasks, pause to tell whether it exists
+		if err = h.cron.Pause(name); err == nil {
+			h.cron.Remove(name)
+		}
+	default:
+		http.Error(w, fmt.Sprintf("unknown action %q", action), http.StatusBadRequest)
+		return
+	}
+
+	if errors.Is(err, ErrTaskNotFound) {
+		http.Error(w, fmt.Sprintf("task %s not found", name), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
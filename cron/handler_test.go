@@ -0,0 +1,103 @@
+package cron
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	testingclock "github.com/qingwave/gocorex/utils/clock/testing"
+)
+
+func TestHandler(t *testing.T) {
+	fc := testingclock.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := NewCron(logr.Discard(), WithClock(fc))
+
+	done := make(chan struct{}, 1)
+	c.Add(&Task{Job: SimpleJob("failing", func() error {
+		defer func() { done <- struct{}{} }()
+		return errors.New("boom")
+	}), Schedule: Every(time.Hour)})
+	c.Add(&Task{Job: SimpleJob("daily", func() error { return nil }), Schedule: Daily(9, 30, time.UTC)})
+	defer c.Stop()
+
+	server := httptest.NewServer(NewHandler(c))
+	defer server.Close()
+
+	post := func(name, action string) int {
+		t.Helper()
+		resp, err := http.PostForm(server.URL, url.Values{"name": {name}, "action": {action}})
+		if err != nil {
+			t.Fatalf("failed to post: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	list := func() []TaskStatus {
+		t.Helper()
+		resp, err := http.Get(server.URL)
+		if err != nil {
+			t.Fatalf("failed to get: %v", err)
+		}
+		defer resp.Body.Close()
+
+		var tasks []TaskStatus
+		if err := json.NewDecoder(resp.Body).Decode(&tasks); err != nil {
+			t.Fatalf("failed to decode tasks: %v", err)
+		}
+		return tasks
+	}
+
+	if code := post("failing", "trigger"); code != http.StatusNoContent {
+		t.Fatalf("expected trigger status %d, but got %d", http.StatusNoContent, code)
+	}
+	<-done
+	if code := post("daily", "pause"); code != http.StatusNoContent {
+		t.Fatalf("expected pause status %d, but got %d", http.StatusNoContent, code)
+	}
+
+	var tasks []TaskStatus
+	for i := 0; i < 100; i++ {
+		if tasks = list(); len(tasks) == 2 && tasks[1].LastRun != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, but got %v", tasks)
+	}
+	if daily := tasks[0]; daily.Name != "daily" || daily.Schedule != "daily at 09:30 UTC" ||
+		daily.State != TaskPaused || !daily.Next.Equal(fc.Now().Add(9*time.Hour+30*time.Minute)) {
+		t.Errorf("unexpected task %+v", daily)
+	}
+	if failing := tasks[1]; failing.Schedule != "every 1h0m0s" || failing.LastRun == nil || failing.LastRun.Error != "boom" {
+		t.Errorf("unexpected task %+v", failing)
+	}
+
+	if code := post("daily", "remove"); code != http.StatusNoContent || c.Len() != 1 {
+		t.Errorf("expected task removed, but got status %d and %d tasks", code, c.Len())
+	}
+	if code := post("daily", "resume"); code != http.StatusNotFound {
+		t.Errorf("expected status %d for removed task, but got %d", http.StatusNotFound, code)
+	}
+	if code := post("failing", "stop"); code != http.StatusBadRequest {
+		t.Errorf("expected status %d for unknown action, but got %d", http.StatusBadRequest, code)
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(""))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to put: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, but got %d", http.StatusMethodNotAllowed, resp.StatusCode)
+	}
+}
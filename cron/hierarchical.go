@@ -0,0 +1,212 @@
+package cron
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/qingwave/gocorex/utils/clock"
+)
+
+// HierarchicalTimeWheel is a hierarchical timing wheel like the one of Kafka.
+// The first wheel has wheelSize buckets of one tick, every overflow wheel
+// has wheelSize buckets which span a whole lower wheel, and overflow wheels
+// are created as long delays are added. A timer is only moved when its
+// bucket in an overflow wheel becomes current, then it falls into a lower
+// wheel, so inserting and cancelling a timer are O(1) and far timers are not
+// rescanned on every rotation.
+type HierarchicalTimeWheel struct {
+	mu        sync.Mutex
+	tick      time.Duration
+	wheelSize int64
+	wheels    []*wheel
+	// current is the number of ticks passed since start
+	current int64
+	start   time.Time
+	pending int
+
+	clock   clock.WithTicker
+	stopCh  chan struct{}
+	stopped sync.Once
+}
+
+type wheel struct {
+	// span is the number of ticks of a bucket
+	span    int64
+	buckets []*list.List
+}
+
+// WheelTimer is a one-shot timer returned by HierarchicalTimeWheel.AfterFunc.
+type WheelTimer struct {
+	tw         *HierarchicalTimeWheel
+	expiration int64
+	f          func()
+
+	bucket *list.List
+	elem   *list.Element
+}
+
+// NewHierarchicalTimeWheel returns a wheel which advances every tick, only
+// the WithClock option applies to it.
+func NewHierarchicalTimeWheel(tick time.Duration, wheelSize int, opts ...Option) *HierarchicalTimeWheel {
+	if tick <= 0 || wheelSize <= 1 {
+		panic("invaild tick or wheel size")
+	}
+
+	option := newOptions(opts...)
+	tw := &HierarchicalTimeWheel{
+		tick:      tick,
+		wheelSize: int64(wheelSize),
+		start:     option.clock.Now(),
+		clock:     option.clock,
+		stopCh:    make(chan struct{}),
+	}
+	tw.wheels = []*wheel{tw.newWheel(1)}
+	return tw
+}
+
+func (tw *HierarchicalTimeWheel) newWheel(span int64) *wheel {
+	w := &wheel{
+		span:    span,
+		buckets: make([]*list.List, tw.wheelSize),
+	}
+	for i := range w.buckets {
+		w.buckets[i] = list.New()
+	}
+	return w
+}
+
+// AfterFunc calls f in its own goroutine after d, rounded up to the tick.
+func (tw *HierarchicalTimeWheel) AfterFunc(d time.Duration, f func()) *WheelTimer {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	// round up against the time of the current tick, so f never runs early
+	elapsed := tw.clock.Since(tw.start) - time.Duration(tw.current)*tw.tick + d
+	ticks := int64((elapsed + tw.tick - 1) / tw.tick)
+
+	t := &WheelTimer{
+		tw:         tw,
+		expiration: tw.current + ticks,
+		f:          f,
+	}
+	tw.add(t)
+	return t
+}
+
+// add puts t into the lowest wheel whose buckets can tell its expiration
+// apart, or runs it if it has expired. The caller must hold the lock.
+func (tw *HierarchicalTimeWheel) add(t *WheelTimer) {
+	delay := t.expiration - tw.current
+	if delay <= 0 {
+		t.bucket, t.elem = nil, nil
+		go t.f()
+		return
+	}
+
+	level, span := 0, int64(1)
+	for delay >= span*tw.wheelSize {
+		span *= tw.wheelSize
+		level++
+		if level == len(tw.wheels) {
+			// overflow wheel
+			tw.wheels = append(tw.wheels, tw.newWheel(span))
+		}
+	}
+
+	w := tw.wheels[level]
+	t.bucket = w.buckets[(t.expiration/w.span)%tw.wheelSize]
+	t.elem = t.bucket.PushBack(t)
+	tw.pending++
+}
+
+// Stop prevents the timer from firing, it returns false if the timer has
+// already fired or been stopped.
+func (t *WheelTimer) Stop() bool {
+	t.tw.mu.Lock()
+	defer t.tw.mu.Unlock()
+
+	if t.bucket == nil {
+		return false
+	}
+
+	t.bucket.Remove(t.elem)
+	t.bucket, t.elem = nil, nil
+	t.tw.pending--
+	return true
+}
+
+// Len returns the number of pending timers.
+func (tw *HierarchicalTimeWheel) Len() int {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.pending
+}
+
+// Run advances the wheel every tick until Stop is called.
+func (tw *HierarchicalTimeWheel) Run() error {
+	ticker := tw.clock.NewTicker(tw.tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-tw.stopCh:
+			return nil
+		case now := <-ticker.C():
+			tw.advanceTo(now)
+		}
+	}
+}
+
+// Stop stops advancing the wheel, it is safe to call Stop more than once.
+func (tw *HierarchicalTimeWheel) Stop() {
+	tw.stopped.Do(func() {
+		close(tw.stopCh)
+	})
+}
+
+// advanceTo advances the wheel tick by tick to now, so the ticks dropped
+// by a lagging ticker are not lost.
+func (tw *HierarchicalTimeWheel) advanceTo(now time.Time) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	target := int64(now.Sub(tw.start) / tw.tick)
+	for tw.current < target {
+		tw.advance()
+	}
+}
+
+// advance moves forward one tick. The buckets of the overflow wheels which
+// become current are moved down first, then the timers in the current bucket
+// of the first wheel are run.
+func (tw *HierarchicalTimeWheel) advance() {
+	tw.current++
+
+	top := 0
+	for level := 1; level < len(tw.wheels); level++ {
+		if tw.current%tw.wheels[level].span != 0 {
+			break
+		}
+		top = level
+	}
+
+	for level := top; level >= 1; level-- {
+		w := tw.wheels[level]
+		tw.flush(w.buckets[(tw.current/w.span)%tw.wheelSize])
+	}
+
+	tw.flush(tw.wheels[0].buckets[tw.current%tw.wheelSize])
+}
+
+// flush removes all timers from bucket and adds them again, so they are
+// moved to a lower wheel or run.
+func (tw *HierarchicalTimeWheel) flush(bucket *list.List) {
+	for e := bucket.Front(); e != nil; {
+		next := e.Next()
+		t := bucket.Remove(e).(*WheelTimer)
+		tw.pending--
+		tw.add(t)
+		e = next
+	}
+}
@@ -0,0 +1,143 @@
+package cron
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	testingclock "github.com/qingwave/gocorex/utils/clock/testing"
+)
+
+func TestHierarchicalTimeWheel(t *testing.T) {
+	fc := testingclock.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	tw := NewHierarchicalTimeWheel(time.Second, 4, WithClock(fc))
+
+	fired := make(chan int, 16)
+	delays := []int{1, 3, 4, 5, 16, 17, 63, 70}
+	for _, d := range delays {
+		d := d
+		tw.AfterFunc(time.Duration(d)*time.Second, func() { fired <- d })
+	}
+	cancelled := tw.AfterFunc(30*time.Second, func() { fired <- 30 })
+	tw.AfterFunc(0, func() { fired <- 0 })
+
+	expectFired := func(expected int) {
+		t.Helper()
+		select {
+		case got := <-fired:
+			if got != expected {
+				t.Errorf("expected timer %d fired, but got %d", expected, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected timer %d fired, but not", expected)
+		}
+	}
+
+	expectFired(0)
+	if tw.Len() != len(delays)+1 {
+		t.Errorf("expected %d pending timers, but got %d", len(delays)+1, tw.Len())
+	}
+
+	if !cancelled.Stop() || cancelled.Stop() {
+		t.Errorf("expected the timer to be stopped only once")
+	}
+
+	next := 0
+	for sec := 1; sec <= 80; sec++ {
+		fc.Step(time.Second)
+		tw.advanceTo(fc.Now())
+		if next < len(delays) && delays[next] == sec {
+			expectFired(sec)
+			next++
+		}
+	}
+
+	select {
+	case got := <-fired:
+		t.Errorf("expected no more timers fired, but got %d", got)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if tw.Len() != 0 {
+		t.Errorf("expected no pending timers, but got %d", tw.Len())
+	}
+}
+
+func TestHierarchicalTimeWheelRun(t *testing.T) {
+	fc := testingclock.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	tw := NewHierarchicalTimeWheel(time.Second, 8, WithClock(fc))
+
+	fired := make(chan time.Time, 1)
+	// 1.5s is rounded up to 2 ticks
+	tw.AfterFunc(1500*time.Millisecond, func() { fired <- fc.Now() })
+
+	go tw.Run()
+	defer tw.Stop()
+
+	fc.BlockUntil(1)
+	// ticks dropped by the ticker are caught up
+	fc.Step(3 * time.Second)
+
+	select {
+	case got := <-fired:
+		if !got.Equal(fc.Now()) {
+			t.Errorf("expected timer fired at %v, but got %v", fc.Now(), got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected timer fired")
+	}
+}
+
+func BenchmarkHierarchicalTimeWheelAfterFunc(b *testing.B) {
+	tw := NewHierarchicalTimeWheel(time.Millisecond, 512)
+	f := func() {}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tw.AfterFunc(time.Duration(i%100000)*time.Millisecond+time.Hour, f)
+	}
+}
+
+func BenchmarkHierarchicalTimeWheelStop(b *testing.B) {
+	tw := NewHierarchicalTimeWheel(time.Millisecond, 512)
+	f := func() {}
+	timers := make([]*WheelTimer, b.N)
+	for i := range timers {
+		timers[i] = tw.AfterFunc(time.Duration(i%100000)*time.Millisecond+time.Hour, f)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		timers[i].Stop()
+	}
+}
+
+func BenchmarkCronAdd(b *testing.B) {
+	c := NewCron(logr.Discard())
+	tasks := make([]*Task, b.N)
+	for i := range tasks {
+		job := SimpleJob(strconv.Itoa(i), func() error { return nil })
+		tasks[i] = &Task{Job: job, Schedule: Once(time.Now().Add(time.Duration(i%100000)*time.Millisecond + time.Hour))}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Add(tasks[i])
+	}
+}
+
+func BenchmarkCronRemove(b *testing.B) {
+	c := NewCron(logr.Discard())
+	names := make([]string, b.N)
+	for i := range names {
+		names[i] = strconv.Itoa(i)
+		job := SimpleJob(names[i], func() error { return nil })
+		c.Add(&Task{Job: job, Schedule: Once(time.Now().Add(time.Duration(i%100000)*time.Millisecond + time.Hour))})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Remove(names[i])
+	}
+}
@@ -0,0 +1,79 @@
+package cron
+
+import (
+	"sync"
+	"time"
+)
+
+// Execution describes a run of a job.
+type Execution struct {
+	Name string
+	// Scheduled is the time the run was due.
+	Scheduled time.Time
+	// Start is zero if the run was skipped.
+	Start    time.Time
+	Duration time.Duration
+	// Attempt is the number of attempts made, more than one if the run was retried.
+	Attempt int
+	Err     error
+}
+
+// Hooks are called during the lifecycle of every run, they are called
+// synchronously so they should not block. They are called without holding
+// the locks of the scheduler, so they may call its methods, e.g. Pause.
+type Hooks struct {
+	// OnStart is called before a run starts.
+	OnStart func(Execution)
+	// OnSuccess is called after a run succeeds.
+	OnSuccess func(Execution)
+	// OnFailure is called after a run fails, when its retries are exhausted.
+	OnFailure func(Execution)
+	// OnSkip is called when a run is skipped according to ForbidConcurrent.
+	OnSkip func(Execution)
+}
+
+func (h *Hooks) call(hook func(Execution), e Execution) {
+	if hook != nil {
+		hook(e)
+	}
+}
+
+const defaultHistoryLimit = 10
+
+// history keeps the latest finished runs of every job.
+type history struct {
+	mu     sync.RWMutex
+	limit  int
+	record map[string][]Execution
+}
+
+func newHistory(limit int) *history {
+	return &history{
+		limit:  limit,
+		record: make(map[string][]Execution),
+	}
+}
+
+func (h *history) add(e Execution) {
+	if h.limit <= 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	record := append(h.record[e.Name], e)
+	if len(record) > h.limit {
+		// copy to release the backing array of old records
+		record = append([]Execution(nil), record[len(record)-h.limit:]...)
+	}
+	h.record[e.Name] = record
+}
+
+// get returns a copy of the runs of name, the oldest first.
+func (h *history) get(name string) []Execution {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return append([]Execution(nil), h.record[name]...)
+}
@@ -0,0 +1,188 @@
+package cron
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// Jitter delays every run of s by a random duration in [0, max), so replicas
+// with the same schedule do not run at the same instant. The delay does not
+// accumulate, the next run is computed from the undelayed time of the
+// previous one.
+func Jitter(s Schedule, max time.Duration) Schedule {
+	if max <= 0 {
+		panic(fmt.Sprintf("invaild jitter %v", max))
+	}
+
+	return &jitterSchedule{
+		Schedule: s,
+		max:      max,
+		seed:     rand.Uint64(),
+	}
+}
+
+type jitterSchedule struct {
+	Schedule
+	max time.Duration
+	// seed makes the delays random per schedule and stable per run
+	seed uint64
+
+	// next is the last run returned by Next, jitter included
+	next time.Time
+	// delay is the jitter added to next
+	delay time.Duration
+}
+
+func (s *jitterSchedule) Next(t time.Time) time.Time {
+	from := t
+	if !s.next.IsZero() && !t.Before(s.next) {
+		from = t.Add(-s.delay)
+	}
+
+	next := s.Schedule.Next(from)
+	if next.IsZero() {
+		return next
+	}
+
+	s.delay = s.delayOf(next)
+	s.next = next.Add(s.delay)
+	return s.next
+}
+
+// skip skips the runs of the inner schedule, conservatively up to now-max as
+// the delays of the runs are not known before, and keeps the state so Next
+// goes on from the last run skipped.
+func (s *jitterSchedule) skip(prev, now time.Time, n int) time.Time {
+	sk, ok := s.Schedule.(skipper)
+	if !ok {
+		return prev
+	}
+
+	base := prev
+	if !s.next.IsZero() && !prev.Before(s.next) {
+		base = prev.Add(-s.delay)
+	}
+
+	skipped := sk.skip(base, now.Add(-s.max), n)
+	if skipped.Equal(base) {
+		return prev
+	}
+
+	s.delay = s.delayOf(skipped)
+	s.next = skipped.Add(s.delay)
+	return s.next
+}
+
+// delayOf returns the delay of the undelayed run t.
+func (s *jitterSchedule) delayOf(t time.Time) time.Duration {
+	var buf [16]byte
+	binary.LittleEndian.PutUint64(buf[:8], s.seed)
+	binary.LittleEndian.PutUint64(buf[8:], uint64(t.UnixNano()))
+
+	h := fnv.New64a()
+	h.Write(buf[:])
+	return time.Duration(h.Sum64() % uint64(s.max))
+}
+
+// unshift finds the undelayed run among the runs of s in (t-max, t], it
+// is only possible if Next of s can be called without changing its state.
+func (s *jitterSchedule) unshift(t time.Time) time.Time {
+	if !stateless(s.Schedule) {
+		return t
+	}
+
+	for base := s.Schedule.Next(t.Add(-s.max)); !base.IsZero() && !base.After(t); base = s.Schedule.Next(base) {
+		if base.Add(s.delayOf(base)).Equal(t) {
+			return unshift(s.Schedule, base)
+		}
+	}
+	return t
+}
+
+func (s *jitterSchedule) aligned() bool {
+	return isAligned(s.Schedule)
+}
+
+func (s *jitterSchedule) String() string {
+	return fmt.Sprintf("%s with jitter %v", describe(s.Schedule), s.max)
+}
+
+// HashOffset shifts every run of s by a stable offset in [0, max) derived
+// from key, e.g. the host name and the job name, so a fleet spreads the runs
+// of a job over max. HashOffset(MustParse("@hourly"), host, time.Hour) runs
+// hourly at a different minute on each host.
+func HashOffset(s Schedule, key string, max time.Duration) Schedule {
+	if max <= 0 {
+		panic(fmt.Sprintf("invaild offset %v", max))
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(key))
+
+	return &offsetSchedule{
+		Schedule: s,
+		offset:   time.Duration(h.Sum64() % uint64(max)),
+	}
+}
+
+type offsetSchedule struct {
+	Schedule
+	offset time.Duration
+}
+
+func (s *offsetSchedule) Next(t time.Time) time.Time {
+	next := s.Schedule.Next(t.Add(-s.offset))
+	if next.IsZero() {
+		return next
+	}
+	return next.Add(s.offset)
+}
+
+func (s *offsetSchedule) skip(prev, now time.Time, n int) time.Time {
+	sk, ok := s.Schedule.(skipper)
+	if !ok {
+		return prev
+	}
+	return sk.skip(prev.Add(-s.offset), now.Add(-s.offset), n).Add(s.offset)
+}
+
+func (s *offsetSchedule) unshift(t time.Time) time.Time {
+	return unshift(s.Schedule, t.Add(-s.offset))
+}
+
+func (s *offsetSchedule) aligned() bool {
+	return isAligned(s.Schedule)
+}
+
+func (s *offsetSchedule) String() string {
+	return fmt.Sprintf("%s offset %v", describe(s.Schedule), s.offset)
+}
+
+// shifter is implemented by schedules which shift the runs of another one.
+type shifter interface {
+	// unshift returns the run of the inner schedule which t is shifted from.
+	unshift(t time.Time) time.Time
+}
+
+// unshift returns the run of the innermost schedule which the run t of s is
+// shifted from, so replicas with different jitters or offsets agree on it.
+func unshift(s Schedule, t time.Time) time.Time {
+	if sh, ok := s.(shifter); ok {
+		return sh.unshift(t)
+	}
+	return t
+}
+
+// stateless reports whether Next of s only depends on its argument.
+func stateless(s Schedule) bool {
+	switch s := s.(type) {
+	case *specSchedule, *calendarSchedule:
+		return true
+	case *offsetSchedule:
+		return stateless(s.Schedule)
+	}
+	return false
+}
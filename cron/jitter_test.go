@@ -0,0 +1,59 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitter(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, s := range []Schedule{
+		Jitter(Every(time.Hour), 10*time.Minute),
+		Jitter(MustParse("TZ=UTC @hourly"), 10*time.Minute),
+	} {
+		now := start
+		for i := 1; i <= 100; i++ {
+			next := s.Next(now)
+			base := start.Add(time.Duration(i) * time.Hour)
+			if next.Before(base) || !next.Before(base.Add(10*time.Minute)) {
+				t.Fatalf("%s: expected run %d in [%v, %v), but got %v", describe(s), i, base, base.Add(10*time.Minute), next)
+			}
+			now = next
+		}
+	}
+}
+
+func TestHashOffset(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	offsets := make(map[time.Duration]bool)
+	for _, host := range []string{"host-1", "host-2", "host-3", "host-4"} {
+		s := HashOffset(MustParse("TZ=UTC @hourly"), host, time.Hour)
+		offset := s.(*offsetSchedule).offset
+		if offset < 0 || offset >= time.Hour {
+			t.Fatalf("expected offset in [0, 1h), but got %v", offset)
+		}
+		if again := HashOffset(Every(time.Hour), host, time.Hour).(*offsetSchedule).offset; again != offset {
+			t.Errorf("expected stable offset %v for %s, but got %v", offset, host, again)
+		}
+		offsets[offset] = true
+
+		// the first run is at the offset within the hour, strictly after start
+		expected := start.Add(offset)
+		if offset == 0 {
+			expected = expected.Add(time.Hour)
+		}
+		for now, i := start, 0; i < 3; i++ {
+			next := s.Next(now)
+			if !next.Equal(expected) {
+				t.Errorf("%s: expected run at %v, but got %v", describe(s), expected, next)
+			}
+			now, expected = next, next.Add(time.Hour)
+		}
+	}
+
+	if len(offsets) < 2 {
+		t.Errorf("expected the offsets spread, but got %v", offsets)
+	}
+}
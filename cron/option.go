@@ -0,0 +1,72 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/qingwave/gocorex/utils/clock"
+)
+
+type Option func(*CronOption)
+
+type CronOption struct {
+	clock        clock.WithTicker
+	stopTimeout  time.Duration
+	errorHandler func(Job, error)
+	hooks        Hooks
+	historyLimit int
+	lock         *DistributedLock
+	store        StateStore
+	catchUp      CatchUpPolicy
+}
+
+// WithClock sets the clock used to compute schedules and wait for tasks,
+// the real clock is used by default.
+func WithClock(c clock.WithTicker) Option {
+	return func(opt *CronOption) {
+		if c != nil {
+			opt.clock = c
+		}
+	}
+}
+
+// WithStopTimeout makes Stop wait up to timeout for running jobs to finish
+// before their context is cancelled. By default Stop cancels them at once.
+func WithStopTimeout(timeout time.Duration) Option {
+	return func(opt *CronOption) {
+		opt.stopTimeout = timeout
+	}
+}
+
+// WithErrorHandler sets a handler called with the error of every failed run,
+// after the retries of the task are exhausted.
+func WithErrorHandler(handler func(job Job, err error)) Option {
+	return func(opt *CronOption) {
+		opt.errorHandler = handler
+	}
+}
+
+// WithHooks sets the hooks called during the lifecycle of every run.
+func WithHooks(hooks Hooks) Option {
+	return func(opt *CronOption) {
+		opt.hooks = hooks
+	}
+}
+
+// WithHistoryLimit sets how many finished runs are kept for every job,
+// 10 by default, history is disabled if limit is not positive.
+func WithHistoryLimit(limit int) Option {
+	return func(opt *CronOption) {
+		opt.historyLimit = limit
+	}
+}
+
+func newOptions(opts ...Option) *CronOption {
+	option := &CronOption{
+		clock:        clock.RealClock{},
+		historyLimit: defaultHistoryLimit,
+	}
+	for _, opt := range opts {
+		opt(option)
+	}
+	return option
+}
@@ -0,0 +1,334 @@
+// Parts of this file are derived from github.com/robfig/cron, licensed
+// under the MIT License:
+//
+// Copyright (C) 2012 Rob Figueiredo
+// All Rights Reserved.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cron
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse parses a cron expression in the local time zone, see ParseInLocation.
+func Parse(spec string) (Schedule, error) {
+	return ParseInLocation(spec, time.Local)
+}
+
+// MustParse is like Parse but panics if the expression cannot be parsed.
+func MustParse(spec string) Schedule {
+	s, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// ParseInLocation parses a cron expression and returns a Schedule that
+// fires in the given location. It accepts:
+//
+//   - 5 fields: minute hour day-of-month month day-of-week
+//   - 6 fields: second minute hour day-of-month month day-of-week
+//   - descriptors: @yearly (@annually), @monthly, @weekly, @daily (@midnight),
+//     @hourly and @every <duration>
+//
+// Each field is a comma separated list of `*`, `?`, a value `a`, a range `a-b`,
+// optionally followed by a step `/n`. Months and days of week also accept
+// three-letter names, e.g. `jan-mar` or `mon,wed,fri`. A leading
+// `CRON_TZ=<zone>` or `TZ=<zone>` overrides the location.
+func ParseInLocation(spec string, loc *time.Location) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, fmt.Errorf("empty cron spec")
+	}
+	original := spec
+
+	if strings.HasPrefix(spec, "TZ=") || strings.HasPrefix(spec, "CRON_TZ=") {
+		i := strings.Index(spec, " ")
+		if i < 0 {
+			return nil, fmt.Errorf("missing fields after time zone in spec %q", spec)
+		}
+		eq := strings.Index(spec, "=")
+		var err error
+		if loc, err = time.LoadLocation(spec[eq+1 : i]); err != nil {
+			return nil, fmt.Errorf("provided bad location %s: %v", spec[eq+1:i], err)
+		}
+		spec = strings.TrimSpace(spec[i:])
+	}
+
+	if loc == nil {
+		loc = time.Local
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		schedule, err := parseDescriptor(spec, loc)
+		if s, ok := schedule.(*specSchedule); ok {
+			s.spec = original
+		}
+		return schedule, err
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("expected 5 or 6 fields, found %d: %q", len(fields), spec)
+	}
+
+	var err error
+	field := func(field string, r bounds) uint64 {
+		if err != nil {
+			return 0
+		}
+		var bits uint64
+		bits, err = getField(field, r)
+		return bits
+	}
+
+	s := &specSchedule{
+		second:   field(fields[0], seconds),
+		minute:   field(fields[1], minutes),
+		hour:     field(fields[2], hours),
+		dom:      field(fields[3], dom),
+		month:    field(fields[4], months),
+		dow:      field(fields[5], dow),
+		location: loc,
+		spec:     original,
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// fold 7 into 0, both are Sunday
+	if s.dow&(1<<7) > 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+
+	return s, nil
+}
+
+func parseDescriptor(descriptor string, loc *time.Location) (Schedule, error) {
+	all := func(r bounds) uint64 {
+		return getRange(r.min, r.max, 1) | starBit
+	}
+
+	switch descriptor {
+	case "@yearly", "@annually":
+		return &specSchedule{
+			second:   1 << seconds.min,
+			minute:   1 << minutes.min,
+			hour:     1 << hours.min,
+			dom:      1 << dom.min,
+			month:    1 << months.min,
+			dow:      all(dow),
+			location: loc,
+		}, nil
+	case "@monthly":
+		return &specSchedule{
+			second:   1 << seconds.min,
+			minute:   1 << minutes.min,
+			hour:     1 << hours.min,
+			dom:      1 << dom.min,
+			month:    all(months),
+			dow:      all(dow),
+			location: loc,
+		}, nil
+	case "@weekly":
+		return &specSchedule{
+			second:   1 << seconds.min,
+			minute:   1 << minutes.min,
+			hour:     1 << hours.min,
+			dom:      all(dom),
+			month:    all(months),
+			dow:      1 << dow.min,
+			location: loc,
+		}, nil
+	case "@daily", "@midnight":
+		return &specSchedule{
+			second:   1 << seconds.min,
+			minute:   1 << minutes.min,
+			hour:     1 << hours.min,
+			dom:      all(dom),
+			month:    all(months),
+			dow:      all(dow),
+			location: loc,
+		}, nil
+	case "@hourly":
+		return &specSchedule{
+			second:   1 << seconds.min,
+			minute:   1 << minutes.min,
+			hour:     all(hours),
+			dom:      all(dom),
+			month:    all(months),
+			dow:      all(dow),
+			location: loc,
+		}, nil
+	}
+
+	const every = "@every "
+	if strings.HasPrefix(descriptor, every) {
+		duration, err := time.ParseDuration(strings.TrimSpace(descriptor[len(every):]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse duration %s: %v", descriptor, err)
+		}
+		if duration <= 0 {
+			return nil, fmt.Errorf("duration must great than zero: %s", descriptor)
+		}
+		return Every(duration), nil
+	}
+
+	return nil, fmt.Errorf("unrecognized descriptor: %s", descriptor)
+}
+
+// getField returns an Int with the bits set representing all of the times that
+// the field represents or error parsing field value.
+func getField(field string, r bounds) (uint64, error) {
+	var bits uint64
+	for _, expr := range strings.Split(field, ",") {
+		bit, err := getRangeExpr(expr, r)
+		if err != nil {
+			return 0, err
+		}
+		bits |= bit
+	}
+	return bits, nil
+}
+
+// getRangeExpr returns the bits indicated by the given expression:
+//
+//	number | number "-" number [ "/" number ]
+//
+// or error parsing range.
+func getRangeExpr(expr string, r bounds) (uint64, error) {
+	var (
+		start, end, step uint
+		rangeAndStep     = strings.Split(expr, "/")
+		lowAndHigh       = strings.Split(rangeAndStep[0], "-")
+		singleDigit      = len(lowAndHigh) == 1
+		err              error
+		extra            uint64
+	)
+
+	if lowAndHigh[0] == "*" || lowAndHigh[0] == "?" {
+		if !singleDigit {
+			return 0, fmt.Errorf("invalid range: %s", expr)
+		}
+		start = r.min
+		end = r.max
+		extra = starBit
+	} else {
+		start, err = parseIntOrName(lowAndHigh[0], r.names)
+		if err != nil {
+			return 0, err
+		}
+		switch len(lowAndHigh) {
+		case 1:
+			end = start
+		case 2:
+			end, err = parseIntOrName(lowAndHigh[1], r.names)
+			if err != nil {
+				return 0, err
+			}
+		default:
+			return 0, fmt.Errorf("too many hyphens: %s", expr)
+		}
+	}
+
+	switch len(rangeAndStep) {
+	case 1:
+		step = 1
+	case 2:
+		step, err = mustParseInt(rangeAndStep[1])
+		if err != nil {
+			return 0, err
+		}
+
+		// Special handling: "N/step" means "N-max/step".
+		if singleDigit {
+			end = r.max
+		}
+		if step > 1 {
+			extra = 0
+		}
+	default:
+		return 0, fmt.Errorf("too many slashes: %s", expr)
+	}
+
+	if start < r.min {
+		return 0, fmt.Errorf("beginning of range (%d) below minimum (%d): %s", start, r.min, expr)
+	}
+	if end > r.max {
+		return 0, fmt.Errorf("end of range (%d) above maximum (%d): %s", end, r.max, expr)
+	}
+	if start > end {
+		return 0, fmt.Errorf("beginning of range (%d) beyond end of range (%d): %s", start, end, expr)
+	}
+	if step == 0 {
+		return 0, fmt.Errorf("step of range should be a positive number: %s", expr)
+	}
+
+	return getRange(start, end, step) | extra, nil
+}
+
+// parseIntOrName returns the (possibly-named) integer contained in expr.
+func parseIntOrName(expr string, names map[string]uint) (uint, error) {
+	if names != nil {
+		if namedInt, ok := names[strings.ToLower(expr)]; ok {
+			return namedInt, nil
+		}
+	}
+	return mustParseInt(expr)
+}
+
+// mustParseInt parses the given expression as an int or returns an error.
+func mustParseInt(expr string) (uint, error) {
+	num, err := strconv.Atoi(expr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse int from %s: %v", expr, err)
+	}
+	if num < 0 {
+		return 0, fmt.Errorf("negative number (%d) not allowed: %s", num, expr)
+	}
+
+	return uint(num), nil
+}
+
+// getRange returns the bits indicated by the given range and step.
+func getRange(min, max, step uint) uint64 {
+	var bits uint64
+
+	// If step is 1, use shifts.
+	if step == 1 {
+		return ^(math.MaxUint64 << (max + 1)) & (math.MaxUint64 << min)
+	}
+
+	// Else, use a simple loop.
+	for i := min; i <= max; i += step {
+		bits |= 1 << i
+	}
+	return bits
+}
@@ -0,0 +1,82 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("load location failed: %v", err)
+	}
+
+	tests := []struct {
+		spec     string
+		from     string
+		expected string
+	}{
+		{"* * * * *", "2023-07-09T14:45:00Z", "2023-07-09T14:46:00Z"},
+		{"*/15 * * * * *", "2023-07-09T14:45:00Z", "2023-07-09T14:45:15Z"},
+		{"30 2 * * *", "2023-07-09T14:45:00Z", "2023-07-10T02:30:00Z"},
+		{"0 9-17/4 * * mon-fri", "2023-07-08T10:00:00Z", "2023-07-10T09:00:00Z"},
+		{"0 0 1,15 * *", "2023-07-02T00:00:00Z", "2023-07-15T00:00:00Z"},
+		{"0 0 29 feb *", "2023-03-01T00:00:00Z", "2024-02-29T00:00:00Z"},
+		{"0 0 * * 7", "2023-07-10T00:00:00Z", "2023-07-16T00:00:00Z"},
+		{"0 0 13 * fri", "2023-07-08T00:00:00Z", "2023-07-13T00:00:00Z"},
+		{"0 0 31 * *", "2023-04-15T00:00:00Z", "2023-05-31T00:00:00Z"},
+		{"@hourly", "2023-07-09T14:45:00Z", "2023-07-09T15:00:00Z"},
+		{"@daily", "2023-07-09T14:45:00Z", "2023-07-10T00:00:00Z"},
+		{"@weekly", "2023-07-09T14:45:00Z", "2023-07-16T00:00:00Z"},
+		{"@monthly", "2023-07-09T14:45:00Z", "2023-08-01T00:00:00Z"},
+		{"@yearly", "2023-07-09T14:45:00Z", "2024-01-01T00:00:00Z"},
+		{"@every 90s", "2023-07-09T14:45:00Z", "2023-07-09T14:46:30Z"},
+		{"TZ=Asia/Shanghai 0 8 * * *", "2023-07-09T01:00:00Z", "2023-07-10T00:00:00Z"},
+	}
+
+	for _, test := range tests {
+		s, err := ParseInLocation(test.spec, time.UTC)
+		if err != nil {
+			t.Errorf("parse %q failed: %v", test.spec, err)
+			continue
+		}
+
+		from, _ := time.Parse(time.RFC3339, test.from)
+		expected, _ := time.Parse(time.RFC3339, test.expected)
+		if got := s.Next(from); !got.Equal(expected) {
+			t.Errorf("spec %q from %v: expected %v, but got %v", test.spec, from, expected, got)
+		}
+	}
+
+	s, _ := ParseInLocation("0 0 8 * * *", shanghai)
+	from := time.Date(2023, 7, 9, 1, 0, 0, 0, time.UTC)
+	if got := s.Next(from); !got.Equal(time.Date(2023, 7, 10, 0, 0, 0, 0, time.UTC)) || got.Location() != time.UTC {
+		t.Errorf("expected next run in location of given time, but got %v", got)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"1-2-3 * * * *",
+		"a * * * *",
+		"* * * foo *",
+		"@every",
+		"@every -1s",
+		"@unknown",
+		"TZ=Nowhere/City * * * * *",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("expected parse %q failed", spec)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package redisstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/qingwave/gocorex/cron"
+)
+
+// New returns a cron.StateStore which keeps the state of all jobs in the
+// redis hash key, so it can be shared by replicas.
+func New(client *redis.Client, key string) (cron.StateStore, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client must not be nil")
+	}
+
+	if key == "" {
+		return nil, fmt.Errorf("key must not be empty")
+	}
+
+	return &RedisStore{
+		client: client,
+		key:    key,
+	}, nil
+}
+
+type RedisStore struct {
+	client *redis.Client
+	key    string
+}
+
+func (r *RedisStore) LastSuccess(ctx context.Context, name string) (time.Time, error) {
+	val, err := r.client.HGet(ctx, r.key, name).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+
+	return time.Parse(time.RFC3339Nano, val)
+}
+
+func (r *RedisStore) SetLastSuccess(ctx context.Context, name string, t time.Time) error {
+	return r.client.HSet(ctx, r.key, name, t.Format(time.RFC3339Nano)).Err()
+}
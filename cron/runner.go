@@ -0,0 +1,277 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/qingwave/gocorex/retry"
+	"github.com/qingwave/gocorex/utils/clock"
+	"github.com/qingwave/gocorex/utils/wait"
+
+	"github.com/go-logr/logr"
+)
+
+// ConcurrencyPolicy decides what happens when a task is due while its
+// previous run is still executing.
+type ConcurrencyPolicy int
+
+const (
+	// AllowConcurrent starts the new run alongside the running one.
+	AllowConcurrent ConcurrencyPolicy = iota
+	// ForbidConcurrent skips the new run if the previous one is still executing.
+	ForbidConcurrent
+	// ReplaceConcurrent cancels the context of the running one and starts the new run.
+	ReplaceConcurrent
+)
+
+func (p ConcurrencyPolicy) String() string {
+	switch p {
+	case AllowConcurrent:
+		return "Allow"
+	case ForbidConcurrent:
+		return "Forbid"
+	case ReplaceConcurrent:
+		return "Replace"
+	default:
+		return fmt.Sprintf("ConcurrencyPolicy(%d)", int(p))
+	}
+}
+
+// RetryPolicy retries a failed run with exponential backoff before it is
+// reported as failed. Retries happen within the run, so they count against
+// Timeout and, with ForbidConcurrent, make the next ticks be skipped. The
+// backoff is cut short once the context of the run is done.
+type RetryPolicy struct {
+	// Backoff.Steps is the maximum number of attempts, one if it is not positive.
+	Backoff wait.Backoff
+	// Retriable decides whether an error should be retried, all errors are retried if it is nil.
+	Retriable func(error) bool
+}
+
+// taskState tracks the in-flight runs of a task.
+type taskState struct {
+	mu      sync.Mutex
+	running int
+	cancel  context.CancelFunc
+	// lastSuccess is the scheduled time of the latest successful run
+	lastSuccess time.Time
+	// paused tasks are not run when they are due
+	paused bool
+}
+
+// runner executes due tasks for both Cron and TimeWheel.
+type runner struct {
+	logger       logr.Logger
+	clock        clock.WithTicker
+	stopTimeout  time.Duration
+	errorHandler func(Job, error)
+	hooks        Hooks
+	history      *history
+	locker       *DistributedLock
+	store        StateStore
+	catchUp      CatchUpPolicy
+
+	// ctx is the parent of all runs, it is cancelled on stop
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	stopped  bool
+	inflight sync.WaitGroup
+	// skipped are the runs to call OnSkip for, see callSkipped
+	skipped []Execution
+}
+
+func newRunner(logger logr.Logger, option *CronOption) *runner {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &runner{
+		logger:       logger,
+		clock:        option.clock,
+		stopTimeout:  option.stopTimeout,
+		errorHandler: option.errorHandler,
+		hooks:        option.hooks,
+		history:      newHistory(option.historyLimit),
+		locker:       option.lock,
+		store:        option.store,
+		catchUp:      option.catchUp,
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+// run starts a run of the task in a new goroutine according to its
+// concurrency policy, it is only called by the scheduling goroutine.
+func (r *runner) run(task *Task) {
+	r.runScheduled(task, []time.Time{task.next})
+}
+
+// runScheduled runs the occurrences scheduled at the given times one after
+// another in a new goroutine, which counts as one run for the concurrency
+// policy of the task.
+func (r *runner) runScheduled(task *Task, scheduled []time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stopped {
+		return
+	}
+
+	task.initState()
+	state := task.state
+
+	state.mu.Lock()
+	if state.running > 0 {
+		switch task.Concurrency {
+		case ForbidConcurrent:
+			state.mu.Unlock()
+			r.logger.Info(fmt.Sprintf("Skip job [%s], previous run is still running", task.Name()))
+			r.skipped = append(r.skipped, Execution{Name: task.Name(), Scheduled: scheduled[0]})
+			return
+		case ReplaceConcurrent:
+			state.cancel()
+			r.logger.Info(fmt.Sprintf("Replace job [%s], previous run is cancelled", task.Name()))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(r.ctx)
+	state.running++
+	state.cancel = cancel
+	state.mu.Unlock()
+
+	r.inflight.Add(1)
+	go func() {
+		defer func() {
+			cancel()
+			state.mu.Lock()
+			state.running--
+			state.mu.Unlock()
+			r.inflight.Done()
+		}()
+
+		for i, at := range scheduled {
+			if i > 0 && ctx.Err() != nil {
+				return
+			}
+			r.execute(ctx, task, Execution{Name: task.Name(), Scheduled: at})
+		}
+	}()
+}
+
+// callSkipped calls OnSkip for the runs skipped so far. The scheduler calls
+// it once its own lock is released, so the hook may call back into it.
+func (r *runner) callSkipped() {
+	r.mu.Lock()
+	skipped := r.skipped
+	r.skipped = nil
+	r.mu.Unlock()
+
+	for _, e := range skipped {
+		r.hooks.call(r.hooks.OnSkip, e)
+	}
+}
+
+// execute runs the occurrence of task described by e.
+func (r *runner) execute(ctx context.Context, task *Task, e Execution) {
+	if task.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, task.Timeout)
+		defer cancel()
+	}
+
+	if r.locker != nil {
+		release, ok := r.lock(ctx, task, e)
+		if !ok {
+			return
+		}
+		defer release()
+	}
+
+	e.Start = r.clock.Now()
+	r.hooks.call(r.hooks.OnStart, e)
+
+	e.Attempt, e.Err = r.exec(ctx, task)
+	e.Duration = r.clock.Since(e.Start)
+	r.history.add(e)
+
+	if e.Err != nil {
+		r.logger.Info(fmt.Sprintf("Run job [%s] failed: %v", task.Name(), e.Err))
+		r.hooks.call(r.hooks.OnFailure, e)
+		if r.errorHandler != nil {
+			r.errorHandler(task.Job, e.Err)
+		}
+		return
+	}
+	r.logger.Info(fmt.Sprintf("Run job [%s] successfully, duration %v", task.Name(), e.Duration))
+	r.hooks.call(r.hooks.OnSuccess, e)
+	r.saveState(task, e)
+}
+
+// History returns the latest finished runs of the job, the oldest first.
+func (r *runner) History(name string) []Execution {
+	return r.history.get(name)
+}
+
+// exec runs the job once, or until it succeeds or runs out of attempts if
+// the task has a retry policy, and returns the number of attempts made.
+func (r *runner) exec(ctx context.Context, task *Task) (int, error) {
+	if task.Retry == nil {
+		return 1, execJob(ctx, task.Job)
+	}
+
+	attempt := 0
+	retriable := func(err error) bool {
+		if task.Retry.Retriable != nil && !task.Retry.Retriable(err) {
+			return false
+		}
+		r.logger.Info(fmt.Sprintf("Run job [%s] attempt %d failed, retry: %v", task.Name(), attempt, err))
+		return true
+	}
+
+	err := retry.RetryOnConditionWithContext(ctx, r.clock, task.Retry.Backoff, retriable, func() error {
+		attempt++
+		return execJob(ctx, task.Job)
+	})
+	return attempt, err
+}
+
+// stop prevents new runs, waits up to stopTimeout for in-flight runs to
+// finish and then cancels the context of the remaining ones.
+func (r *runner) stop() {
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return
+	}
+	r.stopped = true
+	r.mu.Unlock()
+
+	defer r.cancel()
+
+	if r.stopTimeout <= 0 {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		r.inflight.Wait()
+		close(done)
+	}()
+
+	timer := r.clock.NewTimer(r.stopTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C():
+		r.logger.Info(fmt.Sprintf("Timeout waiting for running jobs after %v, cancel them", r.stopTimeout))
+	}
+}
+
+// execJob adapts a plain Job, which ignores ctx, to a ContextJob.
+func execJob(ctx context.Context, job Job) error {
+	if cj, ok := job.(ContextJob); ok {
+		return cj.ExecContext(ctx)
+	}
+	return job.Exec()
+}
@@ -0,0 +1,308 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	testingclock "github.com/qingwave/gocorex/utils/clock/testing"
+	"github.com/qingwave/gocorex/utils/wait"
+)
+
+type blockingJob struct {
+	started   chan struct{}
+	release   chan struct{}
+	cancelled atomic.Int32
+}
+
+func newBlockingJob() *blockingJob {
+	return &blockingJob{
+		started: make(chan struct{}, 8),
+		release: make(chan struct{}),
+	}
+}
+
+func (j *blockingJob) Name() string {
+	return "blocking"
+}
+
+func (j *blockingJob) Exec() error {
+	return j.ExecContext(context.Background())
+}
+
+func (j *blockingJob) ExecContext(ctx context.Context) error {
+	j.started <- struct{}{}
+	select {
+	case <-j.release:
+	case <-ctx.Done():
+		j.cancelled.Add(1)
+	}
+	return nil
+}
+
+func waitStarted(t *testing.T, job *blockingJob, expected int) {
+	t.Helper()
+	for i := 0; i < expected; i++ {
+		select {
+		case <-job.started:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected %d runs started, but got %d", expected, i)
+		}
+	}
+	select {
+	case <-job.started:
+		t.Errorf("expected %d runs started, but got more", expected)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestConcurrencyPolicy(t *testing.T) {
+	for _, policy := range []ConcurrencyPolicy{AllowConcurrent, ForbidConcurrent, ReplaceConcurrent} {
+		t.Run(policy.String(), func(t *testing.T) {
+			r := newRunner(logr.Discard(), newOptions())
+			job := newBlockingJob()
+			task := &Task{Job: job, Concurrency: policy}
+
+			r.run(task)
+			r.run(task)
+			r.run(task)
+
+			switch policy {
+			case AllowConcurrent:
+				waitStarted(t, job, 3)
+			case ForbidConcurrent:
+				waitStarted(t, job, 1)
+			case ReplaceConcurrent:
+				waitStarted(t, job, 3)
+				for i := 0; i < 100 && job.cancelled.Load() < 2; i++ {
+					time.Sleep(10 * time.Millisecond)
+				}
+				if job.cancelled.Load() != 2 {
+					t.Errorf("expected %d runs cancelled, but got %d", 2, job.cancelled.Load())
+				}
+			}
+			close(job.release)
+		})
+	}
+}
+
+func TestTaskTimeout(t *testing.T) {
+	r := newRunner(logr.Discard(), newOptions())
+	job := newBlockingJob()
+
+	r.run(&Task{Job: job, Timeout: 10 * time.Millisecond})
+	waitStarted(t, job, 1)
+
+	for i := 0; i < 100 && job.cancelled.Load() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if job.cancelled.Load() != 1 {
+		t.Errorf("expected run cancelled by timeout")
+	}
+}
+
+func TestRunnerStop(t *testing.T) {
+	fc := testingclock.NewFakeClock(time.Now())
+
+	stop := func(r *runner) chan struct{} {
+		stopped := make(chan struct{})
+		go func() {
+			r.stop()
+			close(stopped)
+		}()
+		fc.BlockUntil(1)
+		return stopped
+	}
+
+	// drain in-flight runs before timeout
+	{
+		r := newRunner(logr.Discard(), newOptions(WithClock(fc), WithStopTimeout(time.Minute)))
+		job := newBlockingJob()
+		r.run(&Task{Job: job})
+		waitStarted(t, job, 1)
+
+		stopped := stop(r)
+		close(job.release)
+		<-stopped
+
+		if job.cancelled.Load() != 0 {
+			t.Errorf("expected run finished without cancel")
+		}
+	}
+
+	// cancel in-flight runs after timeout
+	{
+		r := newRunner(logr.Discard(), newOptions(WithClock(fc), WithStopTimeout(time.Minute)))
+		job := newBlockingJob()
+		r.run(&Task{Job: job})
+		waitStarted(t, job, 1)
+
+		stopped := stop(r)
+		fc.Step(time.Minute)
+		<-stopped
+
+		r.inflight.Wait()
+		if job.cancelled.Load() != 1 {
+			t.Errorf("expected run cancelled after stop timeout")
+		}
+
+		r.run(&Task{Job: job})
+		waitStarted(t, job, 0)
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	errFailed := errors.New("failed")
+	backoff := wait.Backoff{Duration: time.Millisecond, Factor: 2, Steps: 3}
+
+	tests := []struct {
+		name      string
+		backoff   wait.Backoff
+		failures  int
+		retriable func(error) bool
+		attempts  int32
+		failed    bool
+	}{
+		{name: "succeed after retry", backoff: backoff, failures: 2, attempts: 3},
+		{name: "retries exhausted", backoff: backoff, failures: 5, attempts: 3, failed: true},
+		{name: "not retriable", backoff: backoff, failures: 5, retriable: func(error) bool { return false }, attempts: 1, failed: true},
+		{name: "zero backoff succeeds", failures: 0, attempts: 1},
+		{name: "zero backoff fails", failures: 5, attempts: 1, failed: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			errCh := make(chan error, 1)
+			r := newRunner(logr.Discard(), newOptions(WithErrorHandler(func(job Job, err error) {
+				errCh <- err
+			})))
+
+			var attempts atomic.Int32
+			job := SimpleJob("retry", func() error {
+				if attempts.Add(1) <= int32(test.failures) {
+					return errFailed
+				}
+				return nil
+			})
+
+			r.run(&Task{Job: job, Retry: &RetryPolicy{Backoff: test.backoff, Retriable: test.retriable}})
+			r.inflight.Wait()
+
+			if attempts.Load() != test.attempts {
+				t.Errorf("expected %d attempts, but got %d", test.attempts, attempts.Load())
+			}
+
+			select {
+			case err := <-errCh:
+				if !test.failed || err != errFailed {
+					t.Errorf("unexpected error handled: %v", err)
+				}
+			default:
+				if test.failed {
+					t.Errorf("expected error handled")
+				}
+			}
+		})
+	}
+}
+
+func TestRetryCancelledDuringBackoff(t *testing.T) {
+	errFailed := errors.New("failed")
+	backoff := wait.Backoff{Duration: time.Hour, Steps: 3}
+
+	fc := testingclock.NewFakeClock(time.Now())
+	r := newRunner(logr.Discard(), newOptions(WithClock(fc)))
+
+	var attempts atomic.Int32
+	job := SimpleJob("retry", func() error {
+		attempts.Add(1)
+		return errFailed
+	})
+
+	// stop cancels the run waiting for the backoff
+	r.run(&Task{Job: job, Retry: &RetryPolicy{Backoff: backoff}})
+	fc.BlockUntil(1)
+	r.stop()
+	r.inflight.Wait()
+
+	if attempts.Load() != 1 {
+		t.Errorf("expected 1 attempt, but got %d", attempts.Load())
+	}
+	if h := r.History("retry"); len(h) != 1 || h[0].Err != errFailed {
+		t.Errorf("expected the run failed, but got %v", h)
+	}
+
+	// so does the timeout
+	attempts.Store(0)
+	r = newRunner(logr.Discard(), newOptions(WithClock(fc)))
+	r.run(&Task{Job: job, Timeout: 10 * time.Millisecond, Retry: &RetryPolicy{Backoff: backoff}})
+
+	done := make(chan struct{})
+	go func() {
+		r.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected timeout cancels the backoff")
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("expected 1 attempt, but got %d", attempts.Load())
+	}
+}
+
+func TestHooksAndHistory(t *testing.T) {
+	var started, succeeded, failed, skipped atomic.Int32
+	r := newRunner(logr.Discard(), newOptions(
+		WithHistoryLimit(2),
+		WithHooks(Hooks{
+			OnStart:   func(Execution) { started.Add(1) },
+			OnSuccess: func(Execution) { succeeded.Add(1) },
+			OnFailure: func(Execution) { failed.Add(1) },
+			OnSkip:    func(Execution) { skipped.Add(1) },
+		}),
+	))
+
+	errFailed := errors.New("failed")
+	var runs atomic.Int32
+	task := &Task{Job: SimpleJob("hooks", func() error {
+		if runs.Add(1)%2 == 0 {
+			return errFailed
+		}
+		return nil
+	})}
+
+	for i := 0; i < 3; i++ {
+		r.run(task)
+		r.inflight.Wait()
+	}
+
+	job := newBlockingJob()
+	blocking := &Task{Job: job, Concurrency: ForbidConcurrent}
+	r.run(blocking)
+	r.run(blocking)
+	r.callSkipped()
+	close(job.release)
+	r.inflight.Wait()
+
+	if started.Load() != 4 || succeeded.Load() != 3 || failed.Load() != 1 || skipped.Load() != 1 {
+		t.Errorf("unexpected hooks called, started: %d, succeeded: %d, failed: %d, skipped: %d",
+			started.Load(), succeeded.Load(), failed.Load(), skipped.Load())
+	}
+
+	history := r.History("hooks")
+	if len(history) != 2 {
+		t.Fatalf("expected history len %d, but got %d", 2, len(history))
+	}
+	if history[0].Err != errFailed || history[1].Err != nil || history[1].Attempt != 1 || history[1].Start.IsZero() {
+		t.Errorf("unexpected history: %+v", history)
+	}
+
+	if len(r.History("unknown")) != 0 {
+		t.Errorf("expected empty history of unknown job")
+	}
+}
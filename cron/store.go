@@ -0,0 +1,295 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// StateStore persists the scheduled time of the last successful run of
+// every job, so the runs missed while the process was down can be caught up.
+type StateStore interface {
+	// LastSuccess returns the zero time if the job has never succeeded.
+	LastSuccess(ctx context.Context, name string) (time.Time, error)
+	SetLastSuccess(ctx context.Context, name string, t time.Time) error
+}
+
+// CatchUpPolicy is the maximum number of missed runs to run when a task
+// is added, the latest ones are run if more were missed.
+type CatchUpPolicy int
+
+const (
+	// CatchUpNone drops the missed runs.
+	CatchUpNone CatchUpPolicy = 0
+	// CatchUpOnce runs the latest missed run.
+	CatchUpOnce CatchUpPolicy = 1
+)
+
+// CatchUpAll runs up to n missed runs.
+func CatchUpAll(n int) CatchUpPolicy {
+	return CatchUpPolicy(n)
+}
+
+// WithStateStore records the last successful run of every job in store,
+// and catches up the runs missed since then when a task is added.
+func WithStateStore(store StateStore, policy CatchUpPolicy) Option {
+	return func(opt *CronOption) {
+		opt.store = store
+		opt.catchUp = policy
+	}
+}
+
+// saveState records e as the last successful run of task if it is the latest.
+func (r *runner) saveState(task *Task, e Execution) {
+	if r.store == nil {
+		return
+	}
+
+	task.state.mu.Lock()
+	if !e.Scheduled.After(task.state.lastSuccess) {
+		task.state.mu.Unlock()
+		return
+	}
+	task.state.lastSuccess = e.Scheduled
+	task.state.mu.Unlock()
+
+	if err := r.store.SetLastSuccess(r.ctx, e.Name, e.Scheduled); err != nil {
+		r.logger.Info(fmt.Sprintf("Failed to save state of job [%s]: %v", e.Name, err))
+	}
+}
+
+// restore loads the last successful run of task from the store, runs the
+// missed runs according to the catch-up policy and sets the next run time of
+// task. It returns false if the schedule has no more runs after catching up.
+func (r *runner) restore(task *Task, now time.Time) bool {
+	if r.store == nil {
+		return true
+	}
+
+	last, err := r.store.LastSuccess(r.ctx, task.Name())
+	if err != nil {
+		r.logger.Info(fmt.Sprintf("Failed to load state of job [%s]: %v", task.Name(), err))
+		return true
+	}
+
+	task.initState()
+	task.state.lastSuccess = last
+
+	if last.IsZero() {
+		return true
+	}
+
+	limit := int(r.catchUp)
+	if limit < 0 {
+		limit = 0
+	}
+
+	// walk the schedule from the last success, so the phase of the schedule
+	// is kept and a finished schedule is not run again
+	var missed []time.Time
+	exhausted := false
+	prev := skipMissed(task.Schedule, last, now, limit)
+	for {
+		next := task.Next(prev)
+		if next.IsZero() || next.After(now) {
+			task.next = next
+			exhausted = next.IsZero()
+			break
+		}
+		if !next.After(prev) {
+			// the schedule does not move forward
+			break
+		}
+
+		missed = append(missed, next)
+		if len(missed) > limit {
+			missed = missed[1:]
+		}
+		prev = next
+	}
+
+	if len(missed) > 0 {
+		r.logger.Info(fmt.Sprintf("Catch up job [%s], run %d missed runs since %v", task.Name(), len(missed), last))
+		r.runScheduled(task, missed)
+	}
+
+	return !exhausted
+}
+
+// aligned is implemented by schedules whose runs only depend on the wall
+// clock, not on the time of the previous run, e.g. cron expressions.
+type aligned interface {
+	aligned() bool
+}
+
+func isAligned(s Schedule) bool {
+	a, ok := s.(aligned)
+	return ok && a.aligned()
+}
+
+// skipMissed returns the time to walk the schedule from when the runs since
+// last are missed, so only about the latest limit runs are walked instead of
+// every run since last.
+func skipMissed(s Schedule, last, now time.Time, limit int) time.Time {
+	if !now.After(last) {
+		return last
+	}
+
+	if !isAligned(s) {
+		if sk, ok := s.(skipper); ok {
+			return sk.skip(last, now, limit)
+		}
+		// the runs depend on the previous ones, walk all of them
+		return last
+	}
+
+	if limit == 0 {
+		return now
+	}
+
+	// widen the window before now until it covers limit runs
+	for window := time.Minute; window < now.Sub(last); window *= 2 {
+		from := now.Add(-window)
+		if countRuns(s, from, now, limit) >= limit {
+			return from
+		}
+	}
+	return last
+}
+
+// skipper is implemented by schedules running at a fixed interval, and the
+// decorators of them, which can skip missed runs without calling Next.
+type skipper interface {
+	// skip skips the runs in (prev, now] but the latest n at most, and
+	// returns the time of the last run skipped, or prev if none is.
+	skip(prev, now time.Time, n int) time.Time
+}
+
+// countRuns counts the runs of s in (from, to], up to limit.
+func countRuns(s Schedule, from, to time.Time, limit int) int {
+	n := 0
+	for prev := from; n < limit; n++ {
+		next := s.Next(prev)
+		if next.IsZero() || next.After(to) || !next.After(prev) {
+			break
+		}
+		prev = next
+	}
+	return n
+}
+
+var _ StateStore = &MemoryStore{}
+
+// MemoryStore keeps the state in memory, it does not survive restarts but
+// can be shared by schedulers in one process.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	state map[string]time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		state: make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryStore) LastSuccess(ctx context.Context, name string) (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state[name], nil
+}
+
+func (s *MemoryStore) SetLastSuccess(ctx context.Context, name string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state[name] = t
+	return nil
+}
+
+var _ StateStore = &FileStore{}
+
+// FileStore keeps the state in a json file, which is rewritten atomically
+// on every update.
+type FileStore struct {
+	mu    sync.Mutex
+	path  string
+	state map[string]time.Time
+}
+
+// NewFileStore loads the state from path, the file is created on the first
+// update if it does not exist.
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		path:  path,
+		state: make(map[string]time.Time),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &s.state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %v", path, err)
+	}
+
+	return s, nil
+}
+
+func (s *FileStore) LastSuccess(ctx context.Context, name string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state[name], nil
+}
+
+func (s *FileStore) SetLastSuccess(ctx context.Context, name string, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.state[name]
+	s.state[name] = t
+
+	if err := s.save(); err != nil {
+		if ok {
+			s.state[name] = old
+		} else {
+			delete(s.state, name)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *FileStore) save() error {
+	data, err := json.Marshal(s.state)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
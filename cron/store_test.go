@@ -0,0 +1,204 @@
+package cron
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	testingclock "github.com/qingwave/gocorex/utils/clock/testing"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("create file store failed: %v", err)
+	}
+
+	last, err := store.LastSuccess(ctx, "job")
+	if err != nil || !last.IsZero() {
+		t.Errorf("expected zero last success, but got %v, err: %v", last, err)
+	}
+
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := store.SetLastSuccess(ctx, "job", now); err != nil {
+		t.Fatalf("set last success failed: %v", err)
+	}
+
+	store, err = NewFileStore(path)
+	if err != nil {
+		t.Fatalf("reload file store failed: %v", err)
+	}
+	last, err = store.LastSuccess(ctx, "job")
+	if err != nil || !last.Equal(now) {
+		t.Errorf("expected last success %v, but got %v, err: %v", now, last, err)
+	}
+}
+
+func TestCatchUp(t *testing.T) {
+	ctx := context.Background()
+	fc := testingclock.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	now := fc.Now()
+	last := now.Add(-5*time.Hour - 30*time.Minute)
+
+	tests := []struct {
+		name     string
+		policy   CatchUpPolicy
+		expected []time.Time
+	}{
+		{name: "none", policy: CatchUpNone},
+		{name: "once", policy: CatchUpOnce, expected: []time.Time{now.Add(-30 * time.Minute)}},
+		{name: "all", policy: CatchUpAll(3), expected: []time.Time{
+			now.Add(-150 * time.Minute),
+			now.Add(-90 * time.Minute),
+			now.Add(-30 * time.Minute),
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			store.SetLastSuccess(ctx, "hourly", last)
+
+			c := NewCron(logr.Discard(), WithClock(fc), WithStateStore(store, test.policy)).(*Cron)
+			task := &Task{Job: SimpleJob("hourly", func() error { return nil }), Schedule: Every(time.Hour)}
+			c.Add(task)
+			c.inflight.Wait()
+
+			history := c.History("hourly")
+			if len(history) != len(test.expected) {
+				t.Fatalf("expected %d runs, but got %d", len(test.expected), len(history))
+			}
+			for i := range history {
+				if !history[i].Scheduled.Equal(test.expected[i]) {
+					t.Errorf("expected run scheduled at %v, but got %v", test.expected[i], history[i].Scheduled)
+				}
+			}
+
+			if !task.next.Equal(now.Add(30 * time.Minute)) {
+				t.Errorf("expected next run at %v, but got %v", now.Add(30*time.Minute), task.next)
+			}
+
+			expectedLast := last
+			if len(test.expected) > 0 {
+				expectedLast = test.expected[len(test.expected)-1]
+			}
+			if got, _ := store.LastSuccess(ctx, "hourly"); !got.Equal(expectedLast) {
+				t.Errorf("expected last success %v, but got %v", expectedLast, got)
+			}
+		})
+	}
+
+	// finished schedule is not run again after restart
+	store := NewMemoryStore()
+	store.SetLastSuccess(ctx, "once", now.Add(-time.Hour))
+	c := NewCron(logr.Discard(), WithClock(fc), WithStateStore(store, CatchUpOnce))
+	c.Add(&Task{Job: SimpleJob("once", func() error { return nil }), Schedule: Once(now.Add(-time.Hour))})
+	if c.Len() != 0 {
+		t.Errorf("expected finished task not added")
+	}
+}
+
+// countingSchedule counts the calls of Next.
+type countingSchedule struct {
+	*specSchedule
+	calls int
+}
+
+func (s *countingSchedule) Next(t time.Time) time.Time {
+	s.calls++
+	return s.specSchedule.Next(t)
+}
+
+func TestCatchUpLongOutage(t *testing.T) {
+	ctx := context.Background()
+	fc := testingclock.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 500*int(time.Millisecond), time.UTC))
+	now := fc.Now()
+	last := now.Add(-10*365*24*time.Hour - 250*time.Millisecond)
+
+	for _, policy := range []CatchUpPolicy{CatchUpNone, CatchUpAll(2)} {
+		// interval schedules skip the missed runs at once and keep the phase
+		store := NewMemoryStore()
+		store.SetLastSuccess(ctx, "every", last)
+		c := NewCron(logr.Discard(), WithClock(fc), WithStateStore(store, policy)).(*Cron)
+		task := &Task{Job: SimpleJob("every", func() error { return nil }), Schedule: Every(time.Second)}
+		c.Add(task)
+		c.inflight.Wait()
+
+		history := c.History("every")
+		if len(history) != int(policy) {
+			t.Fatalf("expected %d runs, but got %d", policy, len(history))
+		}
+		for i, e := range history {
+			expected := now.Add(-time.Duration(len(history)-1-i)*time.Second - 250*time.Millisecond)
+			if !e.Scheduled.Equal(expected) {
+				t.Errorf("expected run scheduled at %v, but got %v", expected, e.Scheduled)
+			}
+		}
+		if expected := now.Add(750 * time.Millisecond); !task.next.Equal(expected) {
+			t.Errorf("expected next run at %v, but got %v", expected, task.next)
+		}
+
+		// aligned schedules start the walk shortly before now
+		store.SetLastSuccess(ctx, "spec", last)
+		spec := &countingSchedule{specSchedule: MustParse("* * * * * *").(*specSchedule)}
+		c.Add(&Task{Job: SimpleJob("spec", func() error { return nil }), Schedule: spec})
+		c.inflight.Wait()
+
+		if len(c.History("spec")) != int(policy) {
+			t.Errorf("expected %d runs, but got %d", policy, len(c.History("spec")))
+		}
+		if spec.calls > 1000 {
+			t.Errorf("expected missed runs skipped, but Next is called %d times", spec.calls)
+		}
+	}
+}
+
+// countingEvery counts the calls of Next of an interval schedule.
+type countingEvery struct {
+	*schedule
+	calls int
+}
+
+func (s *countingEvery) Next(t time.Time) time.Time {
+	s.calls++
+	return s.schedule.Next(t)
+}
+
+func TestCatchUpLongOutageDecorated(t *testing.T) {
+	ctx := context.Background()
+	fc := testingclock.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	now := fc.Now()
+	last := now.Add(-30 * 24 * time.Hour)
+
+	for _, policy := range []CatchUpPolicy{CatchUpNone, CatchUpAll(2)} {
+		for name, decorate := range map[string]func(Schedule) Schedule{
+			"hash offset": func(s Schedule) Schedule { return HashOffset(s, "host", time.Second) },
+			"jitter":      func(s Schedule) Schedule { return Jitter(s, 500*time.Millisecond) },
+			"both":        func(s Schedule) Schedule { return Jitter(HashOffset(s, "host", time.Second), 500*time.Millisecond) },
+		} {
+			every := &countingEvery{schedule: Every(time.Second).(*schedule)}
+			store := NewMemoryStore()
+			store.SetLastSuccess(ctx, name, last)
+
+			c := NewCron(logr.Discard(), WithClock(fc), WithStateStore(store, policy)).(*Cron)
+			task := &Task{Job: SimpleJob(name, func() error { return nil }), Schedule: decorate(every)}
+			c.Add(task)
+			c.inflight.Wait()
+
+			if len(c.History(name)) != int(policy) {
+				t.Errorf("%s: expected %d runs, but got %d", name, policy, len(c.History(name)))
+			}
+			if every.calls > 100 {
+				t.Errorf("%s: expected missed runs skipped, but Next is called %d times", name, every.calls)
+			}
+			if !task.next.After(now) || task.next.After(now.Add(3*time.Second)) {
+				t.Errorf("%s: expected next run shortly after %v, but got %v", name, now, task.next)
+			}
+		}
+	}
+}
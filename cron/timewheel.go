@@ -2,24 +2,27 @@ package cron
 
 import (
 	"container/list"
-	"fmt"
+	"sync"
 	"time"
 
-	"github.com/qingwave/gocorex/containerx"
+	"github.com/qingwave/gocorex/utils/clock"
 
 	"github.com/go-logr/logr"
 )
 
 type TimeWheel struct {
+	mu          sync.Mutex
 	interval    time.Duration
 	slots       int
 	currentSlot int
 	tasks       []*list.List
-	set         containerx.Set[string]
+	entries     map[string]*TimeWheelTask
 
-	tricker *time.Ticker
+	tricker clock.Ticker
+	stopCh  chan struct{}
+	stopped sync.Once
 
-	logger logr.Logger
+	*runner
 }
 
 type TimeWheelTask struct {
@@ -30,88 +33,126 @@ type TimeWheelTask struct {
 	circle      int
 }
 
-func NewTimeWheel(interval time.Duration, slots int, logger logr.Logger) Interface {
+func NewTimeWheel(interval time.Duration, slots int, logger logr.Logger, opts ...Option) Interface {
+	option := newOptions(opts...)
+
 	return &TimeWheel{
 		interval: interval,
 		slots:    slots,
 		tasks:    make([]*list.List, slots),
-		set:      containerx.NewSet[string](),
-		logger:   logger,
+		entries:  make(map[string]*TimeWheelTask),
+		stopCh:   make(chan struct{}),
+		runner:   newRunner(logger, option),
 	}
 }
 
 func (tw *TimeWheel) Run() error {
-	tw.tricker = time.NewTicker(tw.interval)
+	tw.tricker = tw.clock.NewTicker(tw.interval)
+	defer tw.tricker.Stop()
 
 	for {
-		now, ok := <-tw.tricker.C
-		if !ok {
-			break
+		select {
+		case <-tw.stopCh:
+			return nil
+		case now := <-tw.tricker.C():
+			tw.tick(now)
 		}
-		tw.RunTask(now, tw.currentSlot)
-		tw.currentSlot = (tw.currentSlot + 1) % tw.slots
 	}
+}
 
-	return nil
+// tick moves the wheel forward by one slot and runs the tasks in it,
+// so currentSlot is always the last slot that has been run.
+func (tw *TimeWheel) tick(now time.Time) {
+	tw.mu.Lock()
+	tw.currentSlot = (tw.currentSlot + 1) % tw.slots
+	slot := tw.currentSlot
+	tw.mu.Unlock()
+
+	tw.RunTask(now, slot)
 }
 
 func (tw *TimeWheel) RunTask(now time.Time, slot int) {
+	defer tw.callSkipped()
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
 	taskList := tw.tasks[slot]
 	if taskList == nil {
 		return
 	}
 
+	// collect due tasks first, as they may be added back to this slot
+	var due []*TimeWheelTask
 	for item := taskList.Front(); item != nil; {
-		task, ok := item.Value.(*TimeWheelTask)
-		if !ok || task == nil {
-			item = item.Next()
-			continue
-		}
+		next := item.Next()
 
-		if task.circle > 0 {
+		task, ok := item.Value.(*TimeWheelTask)
+		switch {
+		case !ok || task == nil || tw.entries[task.Name()] != task:
+			// removed or replaced
+			taskList.Remove(item)
+		case task.circle > 0:
 			task.circle--
-			item = item.Next()
-			continue
+		default:
+			taskList.Remove(item)
+			due = append(due, task)
 		}
 
+		item = next
+	}
+
+	for _, task := range due {
 		// run task
-		go func() {
-			start := time.Now()
-			if err := task.Exec(); err != nil {
-				tw.logger.Info(fmt.Sprintf("Run job [%s] failed: %v", task.Name(), err))
-				return
-			}
-			tw.logger.Info(fmt.Sprintf("Run job [%s] successfully, duration %v", task.Name(), time.Since(start)))
-		}()
+		if !task.paused() {
+			tw.run(&task.Task)
+		}
 
 		// delete or update task
-		next := item.Next()
-		taskList.Remove(item)
-		item = next
-
 		task.next = task.Next(now)
 		if !task.next.IsZero() {
 			tw.add(now, task)
 		} else {
-			tw.Remove(task.Name())
+			delete(tw.entries, task.Name())
 		}
 	}
 }
 
+// Stop stops scheduling and cancels the running jobs, see WithStopTimeout.
+// It is safe to call Stop more than once.
 func (tw *TimeWheel) Stop() {
-	tw.tricker.Stop()
+	tw.stopped.Do(func() {
+		close(tw.stopCh)
+		tw.stop()
+	})
 }
 
 func (tw *TimeWheel) Len() int {
-	return tw.set.Len()
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return len(tw.entries)
 }
 
+// Add adds the task, it replaces the task with the same name.
 func (tw *TimeWheel) Add(task *Task) {
-	tw.add(time.Now(), &TimeWheelTask{
+	defer tw.callSkipped()
+	now := tw.clock.Now()
+	twTask := &TimeWheelTask{
 		Task: *task,
-	})
+	}
+	twTask.initState()
+	if !tw.restore(&twTask.Task, now) {
+		return
+	}
+
+	twTask.initialized = !twTask.next.IsZero()
+
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.add(now, twTask)
 }
 
+// add puts the task into the slot it is due, tasks in the slots which are
+// not in entries are dropped when their slot is run.
 func (tw *TimeWheel) add(now time.Time, task *TimeWheelTask) {
 	if !task.initialized {
 		task.next = task.Next(now)
@@ -120,12 +161,13 @@ func (tw *TimeWheel) add(now time.Time, task *TimeWheelTask) {
 
 	duration := task.next.Sub(now)
 	if duration <= 0 {
-		task.slot = tw.currentSlot + 1
+		task.slot = (tw.currentSlot + 1) % tw.slots
 		task.circle = 0
 	} else {
-		mult := int(duration / tw.interval)
+		// round up, so a task never runs before it is due
+		mult := int((duration + tw.interval - 1) / tw.interval)
 		task.slot = (tw.currentSlot + mult) % tw.slots
-		task.circle = mult / tw.slots
+		task.circle = (mult - 1) / tw.slots
 	}
 
 	if tw.tasks[task.slot] == nil {
@@ -133,9 +175,68 @@ func (tw *TimeWheel) add(now time.Time, task *TimeWheelTask) {
 	}
 
 	tw.tasks[task.slot].PushBack(task)
-	tw.set.Insert(task.Name())
+	tw.entries[task.Name()] = task
 }
 
 func (tw *TimeWheel) Remove(name string) {
-	tw.set.Delete(name)
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	delete(tw.entries, name)
+}
+
+func (tw *TimeWheel) Pause(name string) error {
+	return tw.with(name, func(task *TimeWheelTask) {
+		task.setPaused(true)
+	})
+}
+
+func (tw *TimeWheel) Resume(name string) error {
+	return tw.with(name, func(task *TimeWheelTask) {
+		task.setPaused(false)
+	})
+}
+
+// TriggerNow runs the task right now according to its concurrency policy,
+// it does not change the next scheduled run.
+func (tw *TimeWheel) TriggerNow(name string) error {
+	defer tw.callSkipped()
+	return tw.with(name, func(task *TimeWheelTask) {
+		tw.runScheduled(&task.Task, []time.Time{tw.clock.Now()})
+	})
+}
+
+func (tw *TimeWheel) Reschedule(name string, schedule Schedule) error {
+	return tw.with(name, func(task *TimeWheelTask) {
+		// add a copy and leave the old one in its slot to be dropped
+		rescheduled := &TimeWheelTask{Task: task.Task}
+		rescheduled.Schedule = schedule
+		tw.add(tw.clock.Now(), rescheduled)
+		if rescheduled.next.IsZero() {
+			delete(tw.entries, name)
+		}
+	})
+}
+
+func (tw *TimeWheel) List() []TaskInfo {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	infos := make([]TaskInfo, 0, len(tw.entries))
+	for _, task := range tw.entries {
+		infos = append(infos, task.info())
+	}
+	sortTaskInfos(infos)
+	return infos
+}
+
+func (tw *TimeWheel) with(name string, f func(*TimeWheelTask)) error {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	task, ok := tw.entries[name]
+	if !ok {
+		return ErrTaskNotFound
+	}
+	f(task)
+	return nil
 }
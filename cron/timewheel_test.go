@@ -0,0 +1,28 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+func TestTimeWheelStop(t *testing.T) {
+	tw := NewTimeWheel(time.Second, 60, logr.Discard())
+
+	done := make(chan struct{})
+	go func() {
+		tw.Run()
+		close(done)
+	}()
+
+	tw.Stop()
+	// stopping again does not panic
+	tw.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected time wheel stopped")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net/http"
 	"time"
 
 	"github.com/go-logr/stdr"
@@ -32,6 +33,7 @@ func TestCron(c cron.Interface) {
 	addTask(4, cron.EveryAt(now.Add(10*time.Second), 2*time.Second))
 	addTask(5, cron.Once(now.Add(time.Second)))
 	addTask(6, cron.Once(now.Add(-time.Second)))
+	addTask(7, cron.MustParse("*/10 * * * * *"))
 
 	log.Printf("start at %v", time.Now())
 	for i := 0; i <= 60; i++ {
@@ -40,9 +42,14 @@ func TestCron(c cron.Interface) {
 	}
 }
 
-var runTimeWheel = flag.Bool("timewheel", false, "run timingwheel cron, default run mini heap cron")
+var (
+	runTimeWheel = flag.Bool("timewheel", false, "run timingwheel cron, default run mini heap cron")
+	adminAddr    = flag.String("admin", "", "serve the admin handler at the address, e.g. 127.0.0.1:8080")
+)
 
 func main() {
+	flag.Parse()
+
 	var c cron.Interface
 	logger := stdr.New(log.Default())
 	if *runTimeWheel {
@@ -51,5 +58,13 @@ func main() {
 		c = cron.NewCron(logger)
 	}
 
+	if *adminAddr != "" {
+		go func() {
+			if err := http.ListenAndServe(*adminAddr, cron.NewHandler(c)); err != nil {
+				log.Printf("failed to start admin server: %v", err)
+			}
+		}()
+	}
+
 	TestCron(c)
 }
@@ -0,0 +1,173 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/qingwave/gocorex/cron"
+)
+
+// CronState records the runs of cron jobs, pass its hooks to the scheduler
+// by cron.WithHooks(state.WrapHooks(hooks)) and call Watch to export the
+// next runs of the scheduler.
+type CronState struct {
+	option *stateOption
+
+	runsTotal    *prometheus.CounterVec
+	runsDuration *prometheus.HistogramVec
+	lastSuccess  *prometheus.GaugeVec
+	skippedRuns  *prometheus.CounterVec
+	inflightRuns *prometheus.GaugeVec
+	nextRun      *prometheus.Desc
+
+	mu    sync.RWMutex
+	crons []cron.Interface
+}
+
+func NewCronState(opts ...Option) *CronState {
+	option := newStateOption()
+	option.subsystem = "cron"
+	for _, opt := range opts {
+		opt(option)
+	}
+
+	state := &CronState{
+		option: option,
+		runsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: option.namespace,
+				Subsystem: option.subsystem,
+				Name:      "runs_total",
+				Help:      "Number of finished job runs.",
+			},
+			[]string{"job", "result"},
+		),
+		runsDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: option.namespace,
+				Subsystem: option.subsystem,
+				Name:      "run_duration_seconds",
+				Help:      "Duration of job runs.",
+			},
+			[]string{"job"},
+		),
+		lastSuccess: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: option.namespace,
+				Subsystem: option.subsystem,
+				Name:      "last_success_timestamp",
+				Help:      "Time the latest successful run of a job finished.",
+			},
+			[]string{"job"},
+		),
+		skippedRuns: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: option.namespace,
+				Subsystem: option.subsystem,
+				Name:      "skipped_runs_total",
+				Help:      "Number of job runs skipped as the previous run is still running.",
+			},
+			[]string{"job"},
+		),
+		inflightRuns: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: option.namespace,
+				Subsystem: option.subsystem,
+				Name:      "inflight_runs",
+				Help:      "Number of running job runs.",
+			},
+			[]string{"job"},
+		),
+		nextRun: prometheus.NewDesc(
+			prometheus.BuildFQName(option.namespace, option.subsystem, "next_run_timestamp"),
+			"Time the next run of a job is due.",
+			[]string{"job"}, nil,
+		),
+	}
+
+	state.register()
+
+	return state
+}
+
+func (s *CronState) register() {
+	collector := []prometheus.Collector{
+		s.runsTotal,
+		s.runsDuration,
+		s.lastSuccess,
+		s.skippedRuns,
+		s.inflightRuns,
+		s,
+	}
+
+	s.option.register(collector...)
+}
+
+func (s *CronState) Handler() http.Handler {
+	return s.option.handler()
+}
+
+// WrapHooks returns hooks which record the runs and then call hooks.
+func (s *CronState) WrapHooks(hooks cron.Hooks) cron.Hooks {
+	call := func(hook func(cron.Execution), e cron.Execution) {
+		if hook != nil {
+			hook(e)
+		}
+	}
+
+	return cron.Hooks{
+		OnStart: func(e cron.Execution) {
+			s.inflightRuns.WithLabelValues(e.Name).Inc()
+			call(hooks.OnStart, e)
+		},
+		OnSuccess: func(e cron.Execution) {
+			s.finish(e, "success")
+			s.lastSuccess.WithLabelValues(e.Name).Set(float64(e.Start.Add(e.Duration).UnixNano()) / 1e9)
+			call(hooks.OnSuccess, e)
+		},
+		OnFailure: func(e cron.Execution) {
+			s.finish(e, "failure")
+			call(hooks.OnFailure, e)
+		},
+		OnSkip: func(e cron.Execution) {
+			s.skippedRuns.WithLabelValues(e.Name).Inc()
+			call(hooks.OnSkip, e)
+		},
+	}
+}
+
+func (s *CronState) finish(e cron.Execution, result string) {
+	s.inflightRuns.WithLabelValues(e.Name).Dec()
+	s.runsTotal.WithLabelValues(e.Name, result).Inc()
+	s.runsDuration.WithLabelValues(e.Name).Observe(e.Duration.Seconds())
+}
+
+// Watch exports the next runs of the tasks of c, the task names of all
+// watched schedulers must be unique.
+func (s *CronState) Watch(c cron.Interface) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.crons = append(s.crons, c)
+}
+
+func (s *CronState) Describe(ch chan<- *prometheus.Desc) {
+	ch <- s.nextRun
+}
+
+// Collect lists the tasks on scrape, as hooks must not call back into the
+// scheduler.
+func (s *CronState) Collect(ch chan<- prometheus.Metric) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, c := range s.crons {
+		for _, task := range c.List() {
+			if task.Next.IsZero() {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(s.nextRun, prometheus.GaugeValue,
+				float64(task.Next.UnixNano())/1e9, task.Name)
+		}
+	}
+}
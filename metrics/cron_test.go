@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/qingwave/gocorex/cron"
+	testingclock "github.com/qingwave/gocorex/utils/clock/testing"
+)
+
+func TestCronState(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	state := NewCronState(WithNamespace("demo"), WithRegistry(registry))
+
+	fc := testingclock.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	finished := make(chan struct{}, 2)
+	c := cron.NewCron(logr.Discard(), cron.WithClock(fc), cron.WithHooks(state.WrapHooks(cron.Hooks{
+		OnSuccess: func(cron.Execution) { finished <- struct{}{} },
+		OnFailure: func(cron.Execution) { finished <- struct{}{} },
+	})))
+	defer c.Stop()
+	state.Watch(c)
+
+	c.Add(&cron.Task{Job: cron.SimpleJob("ok", func() error { return nil }), Schedule: cron.Every(time.Hour)})
+	c.Add(&cron.Task{Job: cron.SimpleJob("failing", func() error { return errors.New("boom") }), Schedule: cron.Every(time.Hour)})
+	c.TriggerNow("ok")
+	c.TriggerNow("failing")
+	<-finished
+	<-finished
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather: %v", err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			name := family.GetName()
+			for _, label := range m.GetLabel() {
+				name += "," + label.GetValue()
+			}
+			switch {
+			case m.Counter != nil:
+				values[name] = m.Counter.GetValue()
+			case m.Gauge != nil:
+				values[name] = m.Gauge.GetValue()
+			case m.Histogram != nil:
+				values[name] = float64(m.Histogram.GetSampleCount())
+			}
+		}
+	}
+
+	next := float64(fc.Now().Add(time.Hour).Unix())
+	for name, expected := range map[string]float64{
+		"demo_cron_runs_total,ok,success":      1,
+		"demo_cron_runs_total,failing,failure": 1,
+		"demo_cron_run_duration_seconds,ok":    1,
+		"demo_cron_inflight_runs,ok":           0,
+		"demo_cron_last_success_timestamp,ok":  float64(fc.Now().Unix()),
+		"demo_cron_next_run_timestamp,ok":      next,
+		"demo_cron_next_run_timestamp,failing": next,
+	} {
+		if got, ok := values[name]; !ok || got != expected {
+			t.Errorf("expected %s to be %v, but got %v", name, expected, got)
+		}
+	}
+	if _, ok := values["demo_cron_last_success_timestamp,failing"]; ok {
+		t.Errorf("expected no last success of failing job")
+	}
+}
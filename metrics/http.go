@@ -36,6 +36,24 @@ type stateOption struct {
 	registry  Registry
 }
 
+func (o *stateOption) register(collector ...prometheus.Collector) {
+	registerer := prometheus.DefaultRegisterer
+	if o.registry != nil {
+		registerer = o.registry
+	}
+
+	for _, c := range collector {
+		registerer.MustRegister(c)
+	}
+}
+
+func (o *stateOption) handler() http.Handler {
+	if o.registry != nil {
+		return promhttp.HandlerFor(o.registry, promhttp.HandlerOpts{})
+	}
+	return promhttp.Handler()
+}
+
 type Registry interface {
 	prometheus.Registerer
 	prometheus.Gatherer
@@ -120,21 +138,11 @@ func (s *HttpState) register() {
 		s.responseSize,
 	}
 
-	registerer := prometheus.DefaultRegisterer
-	if s.option.registry != nil {
-		registerer = s.option.registry
-	}
-
-	for _, c := range collector {
-		registerer.MustRegister(c)
-	}
+	s.option.register(collector...)
 }
 
 func (s *HttpState) Handler() http.Handler {
-	if s.option.registry != nil {
-		return promhttp.HandlerFor(s.option.registry, promhttp.HandlerOpts{})
-	}
-	return promhttp.Handler()
+	return s.option.handler()
 }
 
 func (s *HttpState) WrapMetrics(h http.Handler) http.Handler {
@@ -1,6 +1,11 @@
 package retry
 
-import "github.com/qingwave/gocorex/utils/wait"
+import (
+	"context"
+
+	"github.com/qingwave/gocorex/utils/clock"
+	"github.com/qingwave/gocorex/utils/wait"
+)
 
 func RetryOnError(backoff wait.Backoff, fn func() error) error {
 	return RetryOnCondition(backoff, func(err error) bool {
@@ -27,3 +32,25 @@ func RetryOnCondition(backoff wait.Backoff, retriable func(error) bool, fn func(
 	}
 	return err
 }
+
+// RetryOnConditionWithContext is like RetryOnCondition, but it waits between
+// attempts on clock and stops retrying once ctx is done, returning the last
+// error. It makes one attempt if backoff.Steps is not positive.
+func RetryOnConditionWithContext(ctx context.Context, c clock.Clock, backoff wait.Backoff, retriable func(error) bool, fn func() error) error {
+	// Step decreases backoff.Steps
+	attempts := backoff.Steps
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= attempts || ctx.Err() != nil || !retriable(err) {
+			return err
+		}
+
+		timer := c.NewTimer(backoff.Step())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C():
+		}
+	}
+}
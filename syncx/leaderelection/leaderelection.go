@@ -42,7 +42,8 @@ type LeaderElectionConfig struct {
 }
 
 type LeaderCallbacks struct {
-	// OnStartedLeading is called when a LeaderElector client starts leading
+	// OnStartedLeading is called when a LeaderElector client starts leading,
+	// its context is done once the leadership is lost
 	OnStartedLeading func(context.Context)
 	// OnStoppedLeading is called when a LeaderElector client stops leading
 	OnStoppedLeading func()
@@ -81,11 +82,28 @@ func (le *EtcdLeaderElection) Run(ctx context.Context) error {
 		return err
 	}
 
-	le.Callbacks.OnStartedLeading(ctx)
+	// leadership is lost once the session is done, e.g. its lease expired
+	leaderCtx, stop := withDone(ctx, le.session.Done())
+	defer stop()
+
+	le.Callbacks.OnStartedLeading(leaderCtx)
 
 	return nil
 }
 
+// withDone returns a context of parent which is also cancelled once done is closed.
+func withDone(parent context.Context, done <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
 func (le *EtcdLeaderElection) observe(ctx context.Context) {
 	if le.Callbacks.OnNewLeader == nil {
 		return
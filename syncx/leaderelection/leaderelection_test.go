@@ -0,0 +1,37 @@
+package leaderelection
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithDone(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the session is done, e.g. its lease expired, the caller's ctx is not
+	done := make(chan struct{})
+	ctx, stop := withDone(parent, done)
+	defer stop()
+
+	close(done)
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected context done when leadership lost")
+	}
+
+	if parent.Err() != nil {
+		t.Errorf("expected caller's context not cancelled")
+	}
+
+	ctx, stop = withDone(parent, make(chan struct{}))
+	defer stop()
+	cancel()
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected context done when caller's context done")
+	}
+}
@@ -0,0 +1,324 @@
+package testing
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/qingwave/gocorex/utils/clock"
+)
+
+var (
+	_ = clock.PassiveClock(&FakePassiveClock{})
+	_ = clock.WithTickerAndDelayedExecution(&FakeClock{})
+)
+
+// FakePassiveClock implements PassiveClock, but returns an arbitrary time.
+type FakePassiveClock struct {
+	lock sync.RWMutex
+	time time.Time
+}
+
+// FakeClock implements clock.WithTickerAndDelayedExecution, but returns an arbitrary time.
+// Time only moves forward when Step or SetTime is called.
+type FakeClock struct {
+	FakePassiveClock
+
+	// waiters are waiting for the fake time to pass their specified time
+	waiters []*fakeClockWaiter
+}
+
+type fakeClockWaiter struct {
+	targetTime   time.Time
+	stepInterval time.Duration
+	destChan     chan time.Time
+	afterFunc    func()
+}
+
+// NewFakePassiveClock returns a new FakePassiveClock.
+func NewFakePassiveClock(t time.Time) *FakePassiveClock {
+	return &FakePassiveClock{
+		time: t,
+	}
+}
+
+// NewFakeClock constructs a fake clock set to the provided time.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{
+		FakePassiveClock: *NewFakePassiveClock(t),
+	}
+}
+
+// Now returns f's time.
+func (f *FakePassiveClock) Now() time.Time {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.time
+}
+
+// Since returns time since the time in f.
+func (f *FakePassiveClock) Since(ts time.Time) time.Duration {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.time.Sub(ts)
+}
+
+// SetTime sets the time on the FakePassiveClock.
+func (f *FakePassiveClock) SetTime(t time.Time) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.time = t
+}
+
+// After is the fake version of time.After(d).
+func (f *FakeClock) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// NewTimer constructs a fake timer, akin to time.NewTimer(d).
+// Like the real one, it fires at once if d is not positive.
+func (f *FakeClock) NewTimer(d time.Duration) clock.Timer {
+	f.lock.Lock()
+	ch := make(chan time.Time, 1) // hold one tick
+	timer := &fakeTimer{
+		fakeClock: f,
+		waiter: fakeClockWaiter{
+			destChan: ch,
+		},
+	}
+	now, due := f.addWaiterLocked(&timer.waiter, d)
+	f.lock.Unlock()
+
+	if due {
+		timer.waiter.fire(now)
+	}
+	return timer
+}
+
+// AfterFunc is the fake version of time.AfterFunc(d, cb).
+// Unlike the real one, cb is called synchronously by the Step or SetTime
+// that makes the timer fire, or by AfterFunc itself if d is not positive,
+// so callers observe its effects on return.
+func (f *FakeClock) AfterFunc(d time.Duration, cb func()) clock.Timer {
+	f.lock.Lock()
+	ch := make(chan time.Time, 1) // hold one tick
+	timer := &fakeTimer{
+		fakeClock: f,
+		waiter: fakeClockWaiter{
+			destChan:  ch,
+			afterFunc: cb,
+		},
+	}
+	now, due := f.addWaiterLocked(&timer.waiter, d)
+	f.lock.Unlock()
+
+	if due {
+		timer.waiter.fire(now)
+	}
+	return timer
+}
+
+// addWaiterLocked sets the one-shot waiter to fire d after now and reports
+// whether it is already due, in which case it is not added and the caller
+// must fire it once the lock is released. The caller must hold the write lock.
+func (f *FakeClock) addWaiterLocked(w *fakeClockWaiter, d time.Duration) (time.Time, bool) {
+	w.targetTime = f.time.Add(d)
+	if d <= 0 {
+		return f.time, true
+	}
+
+	f.waiters = append(f.waiters, w)
+	return f.time, false
+}
+
+// fire notifies the waiter, like the real ones, the tick is dropped if the
+// receiver is lagging behind.
+func (w *fakeClockWaiter) fire(t time.Time) {
+	select {
+	case w.destChan <- t:
+	default:
+	}
+
+	if w.afterFunc != nil {
+		w.afterFunc()
+	}
+}
+
+// Tick constructs a fake ticker, akin to time.Tick.
+func (f *FakeClock) Tick(d time.Duration) <-chan time.Time {
+	return f.NewTicker(d).C()
+}
+
+// NewTicker returns a new Ticker.
+func (f *FakeClock) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	ch := make(chan time.Time, 1) // hold one tick
+	ticker := &fakeTicker{
+		fakeClock: f,
+		waiter: fakeClockWaiter{
+			targetTime:   f.time.Add(d),
+			stepInterval: d,
+			destChan:     ch,
+		},
+	}
+	f.waiters = append(f.waiters, &ticker.waiter)
+	return ticker
+}
+
+// Step moves the clock by Duration and notifies anyone that's called After,
+// Tick, NewTimer, NewTicker or AfterFunc.
+func (f *FakeClock) Step(d time.Duration) {
+	f.lock.Lock()
+	t := f.time.Add(d)
+	due := f.setTimeLocked(t)
+	f.lock.Unlock()
+
+	fireAll(due, t)
+}
+
+// SetTime sets the time and notifies anyone that's called After, Tick,
+// NewTimer, NewTicker or AfterFunc.
+func (f *FakeClock) SetTime(t time.Time) {
+	f.lock.Lock()
+	due := f.setTimeLocked(t)
+	f.lock.Unlock()
+
+	fireAll(due, t)
+}
+
+// setTimeLocked sets the time and returns the waiters whose target time is
+// not after t, ordered by target time, to fire once the lock is released.
+// The caller must hold the write lock.
+func (f *FakeClock) setTimeLocked(t time.Time) []*fakeClockWaiter {
+	f.time = t
+
+	sort.SliceStable(f.waiters, func(i, j int) bool {
+		return f.waiters[i].targetTime.Before(f.waiters[j].targetTime)
+	})
+
+	var due []*fakeClockWaiter
+	newWaiters := make([]*fakeClockWaiter, 0, len(f.waiters))
+	for _, w := range f.waiters {
+		if w.targetTime.After(t) {
+			newWaiters = append(newWaiters, w)
+			continue
+		}
+
+		due = append(due, w)
+
+		if w.stepInterval > 0 {
+			for !w.targetTime.After(t) {
+				w.targetTime = w.targetTime.Add(w.stepInterval)
+			}
+			newWaiters = append(newWaiters, w)
+		}
+	}
+	f.waiters = newWaiters
+
+	return due
+}
+
+// fireAll fires the waiters one by one, so timers and AfterFunc callbacks
+// are notified in the order of their target time.
+func fireAll(waiters []*fakeClockWaiter, t time.Time) {
+	for _, w := range waiters {
+		w.fire(t)
+	}
+}
+
+// HasWaiters returns true if After, Tick, NewTimer, NewTicker or AfterFunc
+// has been called on f but not yet satisfied.
+func (f *FakeClock) HasWaiters() bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return len(f.waiters) > 0
+}
+
+// Waiters returns the number of pending timers, tickers and AfterFunc callbacks.
+func (f *FakeClock) Waiters() int {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return len(f.waiters)
+}
+
+// BlockUntil blocks until f has at least n waiters. It is useful to make sure
+// a goroutine under test has created its timer before the clock is stepped.
+func (f *FakeClock) BlockUntil(n int) {
+	for f.Waiters() < n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Sleep is akin to time.Sleep, it advances the fake clock by d.
+func (f *FakeClock) Sleep(d time.Duration) {
+	f.Step(d)
+}
+
+// removeWaiter removes w from the pending waiters and reports whether it was
+// still pending. The caller must hold the write lock.
+func (f *FakeClock) removeWaiter(w *fakeClockWaiter) bool {
+	for i := range f.waiters {
+		if f.waiters[i] == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+var _ = clock.Timer(&fakeTimer{})
+
+// fakeTimer implements clock.Timer based on a FakeClock.
+type fakeTimer struct {
+	fakeClock *FakeClock
+	waiter    fakeClockWaiter
+}
+
+// C returns the channel that notifies when this timer has fired.
+func (f *fakeTimer) C() <-chan time.Time {
+	return f.waiter.destChan
+}
+
+// Stop prevents the timer from firing. It returns false if the timer has
+// already expired or been stopped.
+func (f *fakeTimer) Stop() bool {
+	f.fakeClock.lock.Lock()
+	defer f.fakeClock.lock.Unlock()
+	return f.fakeClock.removeWaiter(&f.waiter)
+}
+
+// Reset changes the timer to expire after duration d. It returns true if the
+// timer had been active, false if the timer had expired or been stopped.
+func (f *fakeTimer) Reset(d time.Duration) bool {
+	f.fakeClock.lock.Lock()
+	active := f.fakeClock.removeWaiter(&f.waiter)
+	now, due := f.fakeClock.addWaiterLocked(&f.waiter, d)
+	f.fakeClock.lock.Unlock()
+
+	if due {
+		f.waiter.fire(now)
+	}
+	return active
+}
+
+var _ = clock.Ticker(&fakeTicker{})
+
+// fakeTicker implements clock.Ticker based on a FakeClock.
+type fakeTicker struct {
+	fakeClock *FakeClock
+	waiter    fakeClockWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.waiter.destChan
+}
+
+func (t *fakeTicker) Stop() {
+	t.fakeClock.lock.Lock()
+	defer t.fakeClock.lock.Unlock()
+	t.fakeClock.removeWaiter(&t.waiter)
+}
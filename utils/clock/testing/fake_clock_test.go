@@ -0,0 +1,175 @@
+package testing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	c.Step(time.Second)
+	if c.Since(start) != time.Second {
+		t.Errorf("expected since %v, but got %v", time.Second, c.Since(start))
+	}
+
+	c.SetTime(start.Add(time.Hour))
+	if !c.Now().Equal(start.Add(time.Hour)) {
+		t.Errorf("expected now %v, but got %v", start.Add(time.Hour), c.Now())
+	}
+}
+
+func TestFakeTimer(t *testing.T) {
+	c := NewFakeClock(time.Now())
+
+	timer := c.NewTimer(time.Second)
+	stopped := c.NewTimer(time.Second)
+	if !stopped.Stop() {
+		t.Errorf("expected stop an active timer")
+	}
+
+	c.Step(999 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Errorf("timer fired too early")
+	default:
+	}
+
+	c.Step(time.Millisecond)
+	select {
+	case <-timer.C():
+	default:
+		t.Errorf("timer should fire")
+	}
+
+	select {
+	case <-stopped.C():
+		t.Errorf("stopped timer should not fire")
+	default:
+	}
+
+	if c.HasWaiters() {
+		t.Errorf("expected no waiters after timers fired")
+	}
+
+	select {
+	case <-c.NewTimer(0).C():
+	default:
+		t.Errorf("timer with zero duration should fire at once")
+	}
+
+	if timer.Reset(time.Second) {
+		t.Errorf("expected reset an expired timer returns false")
+	}
+	c.Step(time.Second)
+	select {
+	case <-timer.C():
+	default:
+		t.Errorf("reset timer should fire")
+	}
+}
+
+func TestFakeTicker(t *testing.T) {
+	c := NewFakeClock(time.Now())
+
+	ticker := c.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for i := 0; i < 3; i++ {
+		c.Step(time.Second)
+		select {
+		case <-ticker.C():
+		default:
+			t.Errorf("ticker should tick at %d", i)
+		}
+	}
+
+	// lagging receiver only gets one tick
+	c.Step(5 * time.Second)
+	<-ticker.C()
+	select {
+	case <-ticker.C():
+		t.Errorf("expected ticks dropped")
+	default:
+	}
+
+	ticker.Stop()
+	if c.HasWaiters() {
+		t.Errorf("expected no waiters after ticker stopped")
+	}
+}
+
+func TestFakeAfterFunc(t *testing.T) {
+	c := NewFakeClock(time.Now())
+
+	var order []int
+	c.AfterFunc(3*time.Second, func() { order = append(order, 3) })
+	c.AfterFunc(time.Second, func() { order = append(order, 1) })
+	c.AfterFunc(2*time.Second, func() {
+		// callbacks run without the lock held
+		c.Now()
+		order = append(order, 2)
+	})
+
+	c.Step(3 * time.Second)
+	if len(order) != 3 || order[0] != 1 || order[1] != 2 || order[2] != 3 {
+		t.Errorf("expected callbacks run in order, but got %v", order)
+	}
+
+	ran := false
+	c.AfterFunc(0, func() { ran = true })
+	if !ran {
+		t.Errorf("expected callback with zero duration run on return")
+	}
+}
+
+func TestFakeClockFireOrder(t *testing.T) {
+	c := NewFakeClock(time.Now())
+
+	before := c.NewTimer(time.Second)
+	after := c.NewTimer(3 * time.Second)
+	checked := false
+	c.AfterFunc(2*time.Second, func() {
+		checked = true
+		select {
+		case <-before.C():
+		default:
+			t.Errorf("expected earlier timer fired before callback")
+		}
+		select {
+		case <-after.C():
+			t.Errorf("expected later timer fired after callback")
+		default:
+		}
+	})
+
+	c.Step(3 * time.Second)
+	if !checked {
+		t.Errorf("expected callback run")
+	}
+	select {
+	case <-after.C():
+	default:
+		t.Errorf("expected later timer fired")
+	}
+}
+
+func TestBlockUntil(t *testing.T) {
+	c := NewFakeClock(time.Now())
+
+	done := make(chan struct{})
+	go func() {
+		<-c.After(time.Minute)
+		close(done)
+	}()
+
+	c.BlockUntil(1)
+	c.Step(time.Minute)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected waiter released")
+	}
+}